@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sdmrf/relay/internal/app"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/spf13/cobra"
+)
+
+var backupOutput string
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up Burp and relay settings and data",
+	Long: `Write a timestamped archive of Burp's user settings (~/.BurpSuite and, on
+Linux, its Java preferences), the relay user config file and the data
+directory. Archives go to backup.dir unless --output is given.`,
+	Args: cobra.NoArgs,
+	RunE: runBackup,
+}
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "archive file to write")
+	rootCmd.AddCommand(backupCmd)
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	cfg, p, err := loadContext()
+	if err != nil {
+		return err
+	}
+
+	prod, err := product.New(cfg, p)
+	if err != nil {
+		return fmt.Errorf("create product: %w", err)
+	}
+
+	backuper, ok := prod.(product.Backuper)
+	if !ok {
+		return fmt.Errorf("%s does not support backups", prod.Name())
+	}
+
+	backupPlan, err := backuper.ResolveBackup(time.Now())
+	if err != nil {
+		return fmt.Errorf("resolve backup: %w", err)
+	}
+	if backupOutput != "" {
+		if backupPlan.Archive, err = filepath.Abs(backupOutput); err != nil {
+			return fmt.Errorf("resolve output path: %w", err)
+		}
+	}
+
+	if verbose {
+		for _, src := range backupPlan.Sources {
+			fmt.Fprintf(os.Stderr, "Backing up %s from %s\n", src.Name, src.Path)
+		}
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), backupPlan); err != nil {
+		return fmt.Errorf("execute backup: %w", err)
+	}
+
+	return nil
+}
+
+// autoBackup backs up user state before update or remove when
+// backup.auto is set and the product supports backups.
+func autoBackup(cmd *cobra.Command, prod product.Product, enabled bool) error {
+	backuper, ok := prod.(product.Backuper)
+	if !enabled || !ok {
+		return nil
+	}
+
+	backupPlan, err := backuper.ResolveBackup(time.Now())
+	if err != nil {
+		return fmt.Errorf("resolve backup: %w", err)
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), backupPlan); err != nil {
+		return fmt.Errorf("automatic backup failed (set backup.auto to false to skip it): %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/pkg/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configShowOrigin bool
+	configInitForce  bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect relay configuration",
+	Long: `Inspect and edit relay configuration. Settings are merged from, in increasing
+precedence: built-in defaults, the system config file, the user config file,
+the project config file (--config), RELAY_* environment variables and
+command-line flags.
+
+init, set, unset and edit change the user config file, or the file given
+with --config.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long:  `Print the merged configuration. With --origin, print each setting with the layer it came from.`,
+	RunE:  runConfigShow,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented default config file",
+	Long:  `Write the default configuration, with a comment on each setting, to the user config file.`,
+	Args:  cobra.NoArgs,
+	RunE:  runConfigInit,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Long:  `Print the effective value of a setting by dotted key, such as runtime.java.min_version. Lists are comma-separated.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in the config file",
+	Long:  `Set a setting by dotted key. The value is checked against the setting's type and the file is validated before saving. Lists are comma-separated.`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a value from the config file",
+	Long:  `Remove a setting from the config file so the value from a lower layer or the default applies.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Long:  `Open the config file in $VISUAL or $EDITOR. The file is only saved if it is valid; otherwise you can edit again or discard the changes.`,
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEdit,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate config files",
+	Long:  `Validate a config file, or every config layer and the merged result when no file is given. Errors include the line and column of the offending setting.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runConfigValidate,
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file to the current schema",
+	Long: `Rewrite the config file at the current config_version. Older files are
+upgraded in memory on every load; migrate makes the upgrade permanent and
+keeps a backup of the original next to it.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigMigrate,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the config JSON Schema",
+	Long:  `Print a JSON Schema for config files, for editor autocomplete and CI validation.`,
+	Args:  cobra.NoArgs,
+	RunE:  runConfigSchema,
+}
+
+func init() {
+	configShowCmd.Flags().BoolVar(&configShowOrigin, "origin", false, "show the layer each value came from")
+	configInitCmd.Flags().BoolVarP(&configInitForce, "force", "f", false, "overwrite an existing config file")
+	configCmd.AddCommand(configShowCmd, configInitCmd, configGetCmd, configSetCmd, configUnsetCmd, configEditCmd, configValidateCmd, configMigrateCmd, configSchemaCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg, origins, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if !configShowOrigin {
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("encode config: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tORIGIN\tVALUE")
+	for _, key := range config.Keys() {
+		value, err := cfg.Get(key)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key, origins[key], value)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	path, err := configTarget()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil && !configInitForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	data, err := config.Template()
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would write default config to %s\n", path)
+		return nil
+	}
+
+	if err := writeConfigFile(path, data); err != nil {
+		return err
+	}
+
+	fmt.Println("Wrote", path)
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	f, err := openConfigTarget()
+	if err != nil {
+		return err
+	}
+
+	if err := f.Set(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would set %s in %s\n", args[0], f.Path)
+		return nil
+	}
+
+	return f.Write()
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	f, err := openConfigTarget()
+	if err != nil {
+		return err
+	}
+
+	removed, err := f.Unset(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		fmt.Printf("%s is not set in %s\n", args[0], f.Path)
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("[dry-run] Would unset %s in %s\n", args[0], f.Path)
+		return nil
+	}
+
+	return f.Write()
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := configTarget()
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read config: %w", err)
+	}
+	if os.IsNotExist(err) {
+		if original, err = config.Template(); err != nil {
+			return err
+		}
+	}
+
+	// Edit a temporary copy so an invalid file never replaces the original
+	tmp, err := os.CreateTemp("", "relay-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := os.WriteFile(tmp.Name(), original, 0o600); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		if err := runEditor(tmp.Name()); err != nil {
+			return err
+		}
+
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return fmt.Errorf("read temp file: %w", err)
+		}
+
+		f, err := config.ParseFile(path, edited)
+		if err == nil {
+			err = f.Validate()
+		}
+		if err == nil {
+			if bytes.Equal(edited, original) {
+				if _, statErr := os.Stat(path); statErr == nil {
+					fmt.Println("No changes")
+					return nil
+				}
+			}
+			if err := writeConfigFile(path, edited); err != nil {
+				return err
+			}
+			fmt.Println("Saved", path)
+			return nil
+		}
+
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Print("Edit again? [Y/n]: ")
+		response, readErr := reader.ReadString('\n')
+		if readErr != nil {
+			return fmt.Errorf("read response: %w", readErr)
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response == "n" || response == "no" {
+			return fmt.Errorf("invalid config, changes discarded")
+		}
+	}
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		f, err := config.OpenFile(args[0])
+		if err == nil {
+			err = f.Validate()
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(args[0], "is valid")
+		return nil
+	}
+
+	userFile, err := paths.UserConfigFile()
+	if err != nil {
+		return fmt.Errorf("locate user config: %w", err)
+	}
+
+	failed := false
+	for _, path := range []string{paths.SystemConfigFile(), userFile, cfgFile} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		f, err := config.OpenFile(path)
+		if err == nil {
+			err = f.Validate()
+		}
+		if err != nil {
+			fmt.Printf("[✗] %v\n", err)
+			failed = true
+			continue
+		}
+		fmt.Printf("[✓] %s\n", path)
+	}
+
+	if _, _, err := loadConfig(); err != nil {
+		fmt.Printf("[✗] merged: %v\n", err)
+		failed = true
+	} else {
+		fmt.Println("[✓] merged configuration")
+	}
+
+	if failed {
+		return fmt.Errorf("configuration is invalid")
+	}
+	return nil
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	path, err := configTarget()
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	f, err := config.ParseFile(path, original)
+	if err != nil {
+		return err
+	}
+
+	from, err := f.Version()
+	if err != nil {
+		return err
+	}
+
+	applied, err := f.Migrate()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Printf("%s is already at config_version %d\n", path, from)
+		return nil
+	}
+
+	for _, m := range applied {
+		fmt.Printf("v%d -> v%d: %s\n", m.From, m.To, m.Description)
+	}
+
+	if err := f.Validate(); err != nil {
+		return fmt.Errorf("migrated config is invalid: %w", err)
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if dryRun {
+		fmt.Printf("[dry-run] Would back up %s to %s\n", path, backup)
+		fmt.Printf("[dry-run] Would rewrite %s at config_version %d\n", path, config.CurrentVersion)
+		return nil
+	}
+
+	if err := os.WriteFile(backup, original, 0o644); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if err := f.Write(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrated %s to config_version %d (backup: %s)\n", path, config.CurrentVersion, backup)
+	return nil
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	data, err := config.Schema()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// configTarget returns the file init, set, unset and edit write: the
+// --config file when given, otherwise the user config file.
+func configTarget() (string, error) {
+	if rootCmd.PersistentFlags().Changed("config") {
+		return cfgFile, nil
+	}
+
+	path, err := paths.UserConfigFile()
+	if err != nil {
+		return "", fmt.Errorf("locate user config: %w", err)
+	}
+	return path, nil
+}
+
+func openConfigTarget() (*config.File, error) {
+	path, err := configTarget()
+	if err != nil {
+		return nil, err
+	}
+	return config.OpenFile(path)
+}
+
+func writeConfigFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, falling back to vi (notepad on Windows).
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// Editors may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("run editor %s: %w", editor, err)
+	}
+	return nil
+}
+
+// configOverride is a command flag that overrides a config key, such as
+// install --edition for product.edition.
+type configOverride struct {
+	Key   string
+	Flag  string
+	Value string
+}
+
+// loadConfig merges every config layer. Command flag overrides are applied
+// after --set so the more specific flag wins.
+func loadConfig(overrides ...configOverride) (config.Config, config.Origins, error) {
+	return loadConfigFor("", overrides...)
+}
+
+// loadConfigFor is loadConfig with the named install selected; "" selects
+// none.
+func loadConfigFor(install string, overrides ...configOverride) (config.Config, config.Origins, error) {
+	opts := config.LoadOptions{
+		SystemFile:  paths.SystemConfigFile(),
+		ProjectFile: cfgFile,
+		Flags:       map[string]string{},
+		FlagNames:   map[string]string{},
+		Install:     install,
+	}
+
+	userFile, err := paths.UserConfigFile()
+	if err != nil {
+		return config.Config{}, nil, fmt.Errorf("locate user config: %w", err)
+	}
+	opts.UserFile = userFile
+
+	// An explicit --config must exist; the default project file is optional
+	if rootCmd.PersistentFlags().Changed("config") {
+		if _, err := os.Stat(cfgFile); err != nil {
+			return config.Config{}, nil, fmt.Errorf("load config: %w", err)
+		}
+	}
+
+	for _, arg := range setFlags {
+		key, value, err := config.ParseSetFlag(arg)
+		if err != nil {
+			return config.Config{}, nil, err
+		}
+		opts.Flags[key] = value
+		opts.FlagNames[key] = "--set " + key
+	}
+
+	for _, o := range overrides {
+		if o.Value == "" {
+			continue
+		}
+		opts.Flags[o.Key] = o.Value
+		opts.FlagNames[o.Key] = o.Flag
+	}
+
+	cfg, origins, err := config.LoadLayered(opts)
+	if err != nil {
+		return config.Config{}, nil, fmt.Errorf("load config: %w", err)
+	}
+
+	return cfg, origins, nil
+}
+
+// resolvePaths resolves directories for the configured layout.
+func resolvePaths(cfg config.Config) (paths.Paths, error) {
+	p, err := paths.Resolve(pathOptions(cfg))
+	if err != nil {
+		return paths.Paths{}, fmt.Errorf("resolve paths: %w", err)
+	}
+	return p, nil
+}
+
+// pathOptions returns the path resolution options set by cfg.
+func pathOptions(cfg config.Config) paths.Options {
+	return paths.Options{
+		Layout:      paths.Layout(cfg.Layout.Mode),
+		InstallHint: cfg.Paths.Install,
+		DataHint:    cfg.Paths.Data,
+		BinHint:     cfg.Paths.Bin,
+		BackupHint:  cfg.Backup.Dir,
+		Name:        cfg.InstallName,
+	}
+}
+
+// loadContext loads the config of the selected install and resolves paths.
+func loadContext(overrides ...configOverride) (config.Config, paths.Paths, error) {
+	cfg, _, err := loadInstallConfig(overrides...)
+	if err != nil {
+		return config.Config{}, paths.Paths{}, err
+	}
+
+	p, err := resolvePaths(cfg)
+	if err != nil {
+		return config.Config{}, paths.Paths{}, err
+	}
+
+	return cfg, p, nil
+}
@@ -1,84 +1,238 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/sdmrf/relay/internal/app"
 	"github.com/sdmrf/relay/internal/diagnostics"
+	"github.com/sdmrf/relay/internal/launcher"
 	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/sdmrf/relay/pkg/config"
 	"github.com/spf13/cobra"
 )
 
+var (
+	doctorFix  bool     // Apply fixes for failing checks
+	doctorYes  bool     // Skip the confirmation prompt
+	doctorOnly []string // Run only these check IDs
+	doctorSkip []string // Don't run these check IDs
+)
+
 var doctorCmd = &cobra.Command{
 	Use:   "doctor",
 	Short: "Check system readiness",
-	Long:  `Run diagnostic checks to verify the system is ready to run Burp Suite.`,
-	RunE:  runDoctor,
+	Long: `Run diagnostic checks to verify the system is ready to run Burp Suite.
+
+With --fix, relay repairs what it can: it creates missing directories,
+makes them writable, downloads a missing or corrupt JAR or the bundled JRE,
+and regenerates the launcher. The checks then run again.`,
+	RunE: runDoctor,
 }
 
 func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "fix failing checks where possible")
+	doctorCmd.Flags().BoolVarP(&doctorYes, "yes", "y", false, "apply fixes without asking")
+	doctorCmd.Flags().StringSliceVar(&doctorOnly, "only", nil, "run only these checks (e.g. java,network)")
+	doctorCmd.Flags().StringSliceVar(&doctorSkip, "skip", nil, "skip these checks")
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorYes && !doctorFix {
+		return fmt.Errorf("--yes requires --fix")
+	}
+
 	fmt.Println("relay doctor")
 	fmt.Println()
 
-	report := diagnostics.NewReport()
+	env := diagnosticsEnv()
+	cfg, p, pathsErr := env.Config, env.Paths, env.PathsErr
+	sel := diagnostics.Selection{Only: doctorOnly, Skip: doctorSkip}
 
-	// Load config first to get java version requirement
-	cfg, err := config.Load(cfgFile)
+	report, err := diagnostics.Default.Run(cmd.Context(), env, sel)
 	if err != nil {
-		cfg = config.Default()
+		return err
 	}
+	printReport(report)
+	fmt.Println()
 
-	// Check Java
-	report.Add(diagnostics.CheckJava(cfg.Runtime.Java.MinVersion))
+	if doctorFix {
+		fixed, err := applyFixes(cmd.Context(), cfg, p, pathsErr, report)
+		if err != nil {
+			return err
+		}
+		if fixed && !dryRun {
+			after, err := diagnostics.Default.Run(cmd.Context(), env, sel)
+			if err != nil {
+				return err
+			}
+			fmt.Println()
+			fmt.Println("After fixes:")
+			after.PrintChanges(os.Stdout, report)
+			fmt.Println()
+			printReport(after)
+			fmt.Println()
+			report = after
+		}
+	}
 
-	// Check Config
-	report.Add(diagnostics.CheckConfig(cfgFile))
+	if report.HasFailures() {
+		fmt.Println("Some checks failed. Please fix the issues above.")
+		return fmt.Errorf("diagnostics failed")
+	}
 
-	// Resolve and check paths
-	p, err := paths.Resolve(paths.Options{
-		Layout:      paths.Layout(cfg.Layout.Mode),
-		InstallHint: cfg.Paths.Install,
-		DataHint:    cfg.Paths.Data,
-		BinHint:     cfg.Paths.Bin,
-	})
-	if err == nil {
-		report.AddAll(diagnostics.CheckPaths(p))
-		report.Add(diagnostics.CheckProduct(p.InstallDir))
+	if report.HasWarnings() {
+		fmt.Println("All critical checks passed with warnings.")
 	} else {
-		report.Add(diagnostics.Check{
-			Name:    "Paths",
-			Status:  diagnostics.StatusFail,
-			Message: fmt.Sprintf("Failed to resolve: %v", err),
-		})
+		fmt.Println("All checks passed.")
 	}
 
-	// Check network
-	report.Add(diagnostics.CheckNetwork())
+	return nil
+}
 
-	// Print report
+// diagnosticsEnv loads the config and resolves paths for the checks. Errors
+// are kept in the Env for the checks to report rather than returned.
+func diagnosticsEnv() diagnostics.Env {
+	// Load config first to get java version requirement
+	cfg, origins, cfgErr := loadInstallConfig()
+	if cfgErr != nil {
+		cfg = config.Default()
+	}
+
+	// Resolve paths first - the bundled JRE lives in the install dir
+	p, pathsErr := resolvePaths(cfg)
+	prod, prodErr := product.New(cfg, p)
+
+	return diagnostics.Env{
+		Config:      cfg,
+		Origins:     origins,
+		ConfigErr:   cfgErr,
+		Paths:       p,
+		PathsErr:    pathsErr,
+		PathOptions: pathOptions(cfg),
+		Java:        javaOptions(cfg, p),
+		Product:     prod,
+		ProductErr:  prodErr,
+		Offline:     offline,
+	}
+}
+
+func printReport(report *diagnostics.Report) {
 	if verbose {
 		report.PrintVerbose(os.Stdout)
 	} else {
 		report.Print(os.Stdout)
 	}
+}
 
-	fmt.Println()
+// applyFixes lists the fixes for failing checks, asks before applying
+// them, and executes them as a repair plan. It reports whether any fix
+// was attempted.
+func applyFixes(ctx context.Context, cfg config.Config, p paths.Paths, pathsErr error, report *diagnostics.Report) (bool, error) {
+	fixes := report.Fixes()
+	if len(fixes) == 0 {
+		fmt.Println("Nothing relay can fix automatically.")
+		fmt.Println()
+		return false, nil
+	}
+	if pathsErr != nil {
+		return false, pathsErr
+	}
 
-	if report.HasFailures() {
-		fmt.Println("Some checks failed. Please fix the issues above.")
-		return fmt.Errorf("diagnostics failed")
+	fmt.Println("Fixes:")
+	for _, f := range fixes {
+		fmt.Println("  -", f)
 	}
 
-	if report.HasWarnings() {
-		fmt.Println("All critical checks passed with warnings.")
-	} else {
-		fmt.Println("All checks passed.")
+	if !dryRun && !doctorYes {
+		fmt.Print("Apply these fixes? [y/N]: ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && response == "" {
+			return false, fmt.Errorf("read response: %w", err)
+		}
+
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			fmt.Println()
+			return false, nil
+		}
 	}
 
-	return nil
+	repairPlan, err := resolveRepair(ctx, cfg, p, fixes)
+	exec := app.FSExecutor{DryRun: dryRun}
+	if execErr := exec.Execute(ctx, repairPlan); execErr != nil {
+		err = errors.Join(err, execErr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Some fixes failed:\n%v\n", err)
+	}
+	return true, nil
+}
+
+// resolveRepair turns doctor fixes into a repair plan. Fixes that can't
+// be resolved, such as a download while offline, are left out of the plan
+// and returned as errors so the others still run.
+func resolveRepair(ctx context.Context, cfg config.Config, p paths.Paths, fixes []diagnostics.Fix) (plan.RepairPlan, error) {
+	prod, err := product.New(cfg, p)
+	if err != nil {
+		return plan.RepairPlan{}, fmt.Errorf("create product: %w", err)
+	}
+
+	repairPlan := plan.RepairPlan{
+		Product: prod.Name(),
+		Paths:   plan.FromResolved(p),
+		Group:   cfg.Layout.Group,
+	}
+
+	var errs []error
+	regenerate := false
+	for _, f := range fixes {
+		switch f.Action {
+		case diagnostics.FixCreateDir:
+			repairPlan.Dirs = append(repairPlan.Dirs, f.Path)
+		case diagnostics.FixPermissions:
+			repairPlan.Writable = append(repairPlan.Writable, f.Path)
+		case diagnostics.FixJava:
+			if repairPlan.JREArtifact, err = resolveJREArtifact(ctx, cfg, p.InstallDir); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			// An existing launcher still points at the old Java
+			if launchPlan, err := prod.ResolveLaunch(); err == nil {
+				if gen, err := launcher.New(launchPlan, ""); err == nil {
+					if _, err := os.Stat(gen.Path()); err == nil {
+						regenerate = true
+					}
+				}
+			}
+		case diagnostics.FixProduct:
+			installPlan, err := prod.ResolveInstall()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("resolve install: %w", err))
+				continue
+			}
+			repairPlan.Artifact = &installPlan.Artifact
+		case diagnostics.FixLauncher:
+			regenerate = true
+		}
+	}
+
+	if regenerate {
+		launchPlan, err := prod.ResolveLaunch()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolve launch: %w", err))
+		} else {
+			repairPlan.Launch = &launchPlan
+		}
+	}
+
+	return repairPlan, errors.Join(errs...)
 }
@@ -7,9 +7,7 @@ import (
 	"strings"
 
 	"github.com/sdmrf/relay/internal/app"
-	"github.com/sdmrf/relay/internal/paths"
-	"github.com/sdmrf/relay/internal/plan"
-	"github.com/sdmrf/relay/internal/product/burpsuite"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/sdmrf/relay/internal/runtime"
 	"github.com/sdmrf/relay/pkg/config"
 	"github.com/spf13/cobra"
@@ -19,61 +17,68 @@ var (
 	installEdition string
 	installVersion string
 	installYes     bool // Skip confirmation prompts
+	installAll     bool // Install every declared install
 )
 
 var installCmd = &cobra.Command{
 	Use:   "install",
-	Short: "Install Burp Suite",
-	Long:  `Download and install Burp Suite with the specified edition and version.`,
-	RunE:  runInstall,
+	Short: "Install the configured product",
+	Long: `Download and install the product named by product.name (Burp Suite by
+default) with the specified edition and version.`,
+	RunE: runInstall,
 }
 
 func init() {
 	installCmd.Flags().StringVar(&installEdition, "edition", "", "edition to install (professional, community)")
 	installCmd.Flags().StringVar(&installVersion, "version", "", "version to install (default: latest)")
 	installCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "skip confirmation prompts")
+	installCmd.Flags().BoolVar(&installAll, "all", false, "install every install in the installs list")
 	rootCmd.AddCommand(installCmd)
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load(cfgFile)
-	if err != nil {
-		return fmt.Errorf("load config: %w", err)
-	}
-
-	// Override config with flags if provided
-	if installEdition != "" {
-		cfg.Product.Edition = installEdition
+	if !installAll {
+		return installOne(cmd)
 	}
-	if installVersion != "" {
-		cfg.Product.Version = installVersion
+	if installEdition != "" || installVersion != "" {
+		return fmt.Errorf("--edition and --version cannot be combined with --all; set them per install in the config")
 	}
+	return forEachInstall(func() error { return installOne(cmd) })
+}
 
-	p, err := paths.Resolve(paths.Options{
-		Layout:      paths.Layout(cfg.Layout.Mode),
-		InstallHint: cfg.Paths.Install,
-		DataHint:    cfg.Paths.Data,
-		BinHint:     cfg.Paths.Bin,
-	})
+// installOne installs the selected install.
+func installOne(cmd *cobra.Command) error {
+	cfg, p, err := loadContext(
+		configOverride{Key: "product.edition", Flag: "--edition", Value: installEdition},
+		configOverride{Key: "product.version", Flag: "--version", Value: installVersion},
+	)
 	if err != nil {
-		return fmt.Errorf("resolve paths: %w", err)
+		return err
 	}
 
-	burp, err := burpsuite.New(cfg, p)
+	if err := offerRelocation(cfg, p, installYes); err != nil {
+		return err
+	}
+
+	prod, err := product.New(cfg, p)
 	if err != nil {
 		return fmt.Errorf("create product: %w", err)
 	}
 
-	installPlan, err := burp.ResolveInstall()
+	installPlan, err := prod.ResolveInstall()
 	if err != nil {
 		return fmt.Errorf("resolve install: %w", err)
 	}
 
 	// Check if JRE needs to be downloaded
-	needsJRE := runtime.NeedsJRE(p.InstallDir, string(cfg.Runtime.Java.Strategy))
+	needsJRE, err := runtime.NeedsJRE(javaOptions(cfg, p))
+	if err != nil {
+		return fmt.Errorf("check java: %w", err)
+	}
 	if needsJRE {
-		// Prompt user for confirmation
-		if !installYes && !dryRun {
+		// Prompt only when falling back; bundled and managed strategies ask for the JRE explicitly.
+		// Offline, resolving the JRE fails below without asking
+		if cfg.Runtime.Java.Strategy == config.JavaStrategyAuto && !installYes && !dryRun && !offline {
 			fmt.Println("Java runtime not found on your system.")
 			fmt.Print("Download bundled JRE (~50MB)? [y/N]: ")
 
@@ -85,7 +90,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 
 			response = strings.TrimSpace(strings.ToLower(response))
 			if response != "y" && response != "yes" {
-				fmt.Println("\nJava 17+ is required to run Burp Suite.")
+				fmt.Printf("\nJava %d+ is required to run %s.\n", cfg.Runtime.Java.MinVersion, prod.Name())
 				fmt.Println("Install Java manually, then run 'relay install' again.")
 				fmt.Println("\nInstallation options:")
 				fmt.Println("  - macOS:   brew install openjdk@21")
@@ -95,22 +100,16 @@ func runInstall(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		// Build JRE artifact
-		jreArtifact, err := runtime.BuildJREArtifact(p.InstallDir)
+		jreArtifact, err := resolveJREArtifact(cmd.Context(), cfg, p.InstallDir)
 		if err != nil {
-			return fmt.Errorf("build JRE artifact: %w", err)
+			return err
 		}
 
-		installPlan.JREArtifact = &plan.JREArtifact{
-			Name:      jreArtifact.Name,
-			URL:       jreArtifact.URL,
-			Target:    jreArtifact.Target,
-			ExtractTo: jreArtifact.ExtractTo,
-		}
+		installPlan.JREArtifact = jreArtifact
 	}
 
 	if verbose {
-		fmt.Fprintln(os.Stderr, "Installing", burp.Name(), cfg.Product.Edition, cfg.Product.Version)
+		fmt.Fprintln(os.Stderr, "Installing", prod.Name(), cfg.Product.Edition, cfg.Product.Version)
 		if installPlan.JREArtifact != nil {
 			fmt.Fprintln(os.Stderr, "Including bundled JRE:", installPlan.JREArtifact.Name)
 		}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+// loadInstallConfig loads the config of the install commands act on: the
+// one named by --install, or the only one declared. Without installs it
+// is the top-level config.
+func loadInstallConfig(overrides ...configOverride) (config.Config, config.Origins, error) {
+	name := installName
+	if name == "" {
+		cfg, origins, err := loadConfig(overrides...)
+		if err != nil || len(cfg.Installs) == 0 {
+			return cfg, origins, err
+		}
+		if len(cfg.Installs) > 1 {
+			return config.Config{}, nil, fmt.Errorf("config declares several installs (%s): choose one with --install",
+				strings.Join(cfg.InstallNames(), ", "))
+		}
+		name = cfg.Installs[0].Name
+	}
+	return loadConfigFor(name, overrides...)
+}
+
+// forEachInstall runs fn once for each declared install, with --install
+// set to it, or once when none are declared. It carries on past a failed
+// install and returns every error.
+func forEachInstall(fn func() error) error {
+	if installName != "" {
+		return fmt.Errorf("--all and --install cannot be combined")
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	names := cfg.InstallNames()
+	if len(names) == 0 {
+		return fn()
+	}
+
+	defer func() { installName = "" }()
+	var errs []error
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("==> %s\n", name)
+
+		installName = name
+		if err := fn(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
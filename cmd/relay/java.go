@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var javaCmd = &cobra.Command{
+	Use:   "java",
+	Short: "Inspect Java installations",
+	Long:  `Inspect the Java runtimes relay can use to run Burp Suite.`,
+}
+
+var javaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List discovered Java installations",
+	Long: `List every JDK/JRE found in the bundled JRE, JAVA_HOME, PATH, SDKMAN, asdf,
+Homebrew and OS-specific locations. The installation relay would pick for the
+system strategy is marked with '*'.`,
+	RunE: runJavaList,
+}
+
+func init() {
+	javaCmd.AddCommand(javaListCmd)
+	rootCmd.AddCommand(javaCmd)
+}
+
+func runJavaList(cmd *cobra.Command, args []string) error {
+	cfg, p, err := loadContext()
+	if err != nil {
+		return err
+	}
+
+	writeJavaList(os.Stdout, cfg, p)
+	return nil
+}
+
+// writeJavaList writes a table of discovered Java installations to w.
+func writeJavaList(out io.Writer, cfg config.Config, p paths.Paths) {
+	prober := javaProber(p)
+	installs := runtime.Discoverer{InstallDir: p.InstallDir, Probe: prober.Probe}.Discover()
+	if len(installs) == 0 {
+		fmt.Fprintln(out, "No Java installations found.")
+		return
+	}
+
+	// Mark the choice the system strategy would make (bundled excluded)
+	var system []runtime.Installation
+	for _, inst := range installs {
+		if inst.Source != runtime.SourceBundled {
+			system = append(system, inst)
+		}
+	}
+	best, bestErr := runtime.Best(system, cfg.Runtime.Java.MinVersion)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tVERSION\tVENDOR\tARCH\tSOURCE\tPATH")
+	for _, inst := range installs {
+		mark := ""
+		if bestErr == nil && inst.Path == best.Path {
+			mark = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			mark, inst.Version, inst.Vendor, inst.Arch, inst.Source, inst.Path)
+	}
+	w.Flush()
+
+	if bestErr != nil {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "No system Java meets min_version %d.\n", cfg.Runtime.Java.MinVersion)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sdmrf/relay/internal/app"
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jreUpdateForce bool
+)
+
+var jreCmd = &cobra.Command{
+	Use:   "jre",
+	Short: "Manage the bundled Java runtime",
+	Long:  `Inspect, update, or remove the Eclipse Temurin JRE that relay bundles for Burp Suite.`,
+}
+
+var jreInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the bundled JRE",
+	Long:  `Show the installed bundled JRE and the release the configured version resolves to.`,
+	RunE:  runJREInfo,
+}
+
+var jreUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update the bundled JRE",
+	Long:  `Download the release selected by runtime.java.bundled.version and replace the bundled JRE.`,
+	RunE:  runJREUpdate,
+}
+
+var jreRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the bundled JRE",
+	Long:  `Delete the bundled JRE. Burp Suite will use system Java if the strategy allows it.`,
+	RunE:  runJRERemove,
+}
+
+func init() {
+	jreUpdateCmd.Flags().BoolVarP(&jreUpdateForce, "force", "f", false, "re-download even if already at the resolved release")
+	jreCmd.AddCommand(jreInfoCmd, jreUpdateCmd, jreRemoveCmd)
+	rootCmd.AddCommand(jreCmd)
+}
+
+func runJREInfo(cmd *cobra.Command, args []string) error {
+	cfg, p, err := loadContext()
+	if err != nil {
+		return err
+	}
+
+	installed, err := runtime.ReadJREMarker(p.InstallDir)
+	if err != nil {
+		return fmt.Errorf("read JRE marker: %w", err)
+	}
+
+	javaPath := runtime.GetBundledJREPath(p.InstallDir)
+	switch {
+	case javaPath == "":
+		fmt.Println("Installed: none")
+	case installed == "":
+		fmt.Println("Installed: unknown version")
+	default:
+		fmt.Println("Installed:", installed)
+	}
+	if javaPath != "" {
+		fmt.Println("Path:     ", javaPath)
+	}
+
+	spec := cfg.Runtime.Java.Bundled.Version
+	if spec == "" {
+		spec = fmt.Sprint(runtime.DefaultJREFeature)
+	}
+	fmt.Println("Selector: ", spec)
+
+	rel, err := jreResolver(cfg).Resolve(cmd.Context(), cfg.Runtime.Java.Bundled.Version)
+	if err != nil {
+		fmt.Println("Available: unknown")
+		if verbose {
+			fmt.Fprintln(os.Stderr, "resolve JRE:", err)
+		}
+		return nil
+	}
+
+	fmt.Println("Available:", rel.Version)
+	if installed != "" && installed != rel.Version {
+		fmt.Println("\nRun 'relay jre update' to install", rel.Version)
+	}
+
+	return nil
+}
+
+func runJREUpdate(cmd *cobra.Command, args []string) error {
+	cfg, p, err := loadContext()
+	if err != nil {
+		return err
+	}
+
+	current, err := runtime.ReadJREMarker(p.InstallDir)
+	if err != nil {
+		return fmt.Errorf("read JRE marker: %w", err)
+	}
+
+	artifact, err := resolveJREArtifact(cmd.Context(), cfg, p.InstallDir)
+	if err != nil {
+		return err
+	}
+
+	if !jreUpdateForce && current == artifact.Version && runtime.HasBundledJRE(p.InstallDir) {
+		fmt.Println("Bundled JRE already at", current)
+		return nil
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Current JRE: %s\n", displayVersion(current))
+		fmt.Fprintf(os.Stderr, "Target JRE:  %s\n", artifact.Version)
+	}
+
+	runtimePlan := plan.RuntimePlan{
+		Action:         plan.RuntimeInstall,
+		CurrentVersion: current,
+		TargetVersion:  artifact.Version,
+		Paths:          plan.FromResolved(p),
+		JREArtifact:    artifact,
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), runtimePlan); err != nil {
+		return fmt.Errorf("execute JRE update: %w", err)
+	}
+
+	if !dryRun {
+		fmt.Println("JRE update complete")
+	}
+
+	return nil
+}
+
+func runJRERemove(cmd *cobra.Command, args []string) error {
+	_, p, err := loadContext()
+	if err != nil {
+		return err
+	}
+
+	current, err := runtime.ReadJREMarker(p.InstallDir)
+	if err != nil {
+		return fmt.Errorf("read JRE marker: %w", err)
+	}
+
+	if !runtime.HasBundledJRE(p.InstallDir) && current == "" {
+		fmt.Println("No bundled JRE installed")
+		return nil
+	}
+
+	runtimePlan := plan.RuntimePlan{
+		Action:         plan.RuntimeRemove,
+		CurrentVersion: current,
+		Paths:          plan.FromResolved(p),
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), runtimePlan); err != nil {
+		return fmt.Errorf("execute JRE remove: %w", err)
+	}
+
+	if !dryRun {
+		fmt.Println("JRE removal complete")
+	}
+
+	return nil
+}
+
+// jreResolver returns a release resolver for the configured metadata endpoint.
+func jreResolver(cfg config.Config) runtime.JREResolver {
+	return runtime.JREResolver{
+		BaseURL: cfg.Runtime.Java.Bundled.Metadata,
+		Timeout: cfg.Network.Timeout,
+	}
+}
+
+// javaOptions builds the Java selection for the configured strategy.
+func javaOptions(cfg config.Config, p paths.Paths) runtime.JavaOptions {
+	return runtime.JavaOptions{
+		Strategy:   string(cfg.Runtime.Java.Strategy),
+		InstallDir: p.InstallDir,
+		JavaHome:   cfg.Runtime.Java.JavaHome,
+		Pin:        cfg.Runtime.Java.Bundled.Version,
+		MinVersion: cfg.Runtime.Java.MinVersion,
+		Prober:     javaProber(p),
+	}
+}
+
+// javaProber returns a prober that caches results in the cache directory,
+// or one without a cache when that directory isn't relay's to use.
+func javaProber(p paths.Paths) runtime.Prober {
+	if dryRun || paths.ClaimCache(p.CacheDir, p.InstallDir, p.Layout) != nil {
+		return runtime.Prober{}
+	}
+	return runtime.Prober{CacheFile: runtime.ProbeCacheFile(p.CacheDir)}
+}
+
+// resolveJREArtifact resolves the configured bundled JRE release into a plan artifact.
+func resolveJREArtifact(ctx context.Context, cfg config.Config, installDir string) (*plan.JREArtifact, error) {
+	rel, err := jreResolver(cfg).Resolve(ctx, cfg.Runtime.Java.Bundled.Version)
+	if err != nil {
+		return nil, fmt.Errorf("resolve JRE release: %w", err)
+	}
+
+	jre, err := runtime.BuildJREArtifact(installDir, rel)
+	if err != nil {
+		return nil, fmt.Errorf("build JRE artifact: %w", err)
+	}
+
+	return &plan.JREArtifact{
+		Name:      jre.Name,
+		Version:   jre.Version,
+		URL:       jre.URL,
+		Checksum:  jre.Checksum,
+		Target:    jre.Target,
+		ExtractTo: jre.ExtractTo,
+	}, nil
+}
+
+func displayVersion(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
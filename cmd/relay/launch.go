@@ -5,17 +5,17 @@ import (
 	"os"
 
 	"github.com/sdmrf/relay/internal/app"
-	"github.com/sdmrf/relay/internal/paths"
-	"github.com/sdmrf/relay/internal/product/burpsuite"
-	"github.com/sdmrf/relay/pkg/config"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/spf13/cobra"
 )
 
 var launchCmd = &cobra.Command{
-	Use:   "launch",
-	Short: "Launch Burp Suite",
-	Long:  `Start Burp Suite with the configured settings.`,
-	RunE:  runLaunch,
+	Use:   "launch [install]",
+	Short: "Launch the installed product",
+	Long: `Start the configured product with the configured settings. When the config
+declares several installs, name the one to start.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLaunch,
 }
 
 func init() {
@@ -23,33 +23,34 @@ func init() {
 }
 
 func runLaunch(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load(cfgFile)
-	if err != nil {
-		return fmt.Errorf("load config: %w", err)
+	if len(args) == 1 {
+		if installName != "" && installName != args[0] {
+			return fmt.Errorf("launch %s conflicts with --install %s", args[0], installName)
+		}
+		installName = args[0]
 	}
 
-	p, err := paths.Resolve(paths.Options{
-		Layout:      paths.Layout(cfg.Layout.Mode),
-		InstallHint: cfg.Paths.Install,
-		DataHint:    cfg.Paths.Data,
-		BinHint:     cfg.Paths.Bin,
-	})
+	cfg, p, err := loadContext()
 	if err != nil {
-		return fmt.Errorf("resolve paths: %w", err)
+		return err
+	}
+
+	if err := offerRelocation(cfg, p, false); err != nil {
+		return err
 	}
 
-	burp, err := burpsuite.New(cfg, p)
+	prod, err := product.New(cfg, p)
 	if err != nil {
 		return fmt.Errorf("create product: %w", err)
 	}
 
-	launchPlan, err := burp.ResolveLaunch()
+	launchPlan, err := prod.ResolveLaunch()
 	if err != nil {
 		return fmt.Errorf("resolve launch: %w", err)
 	}
 
 	if verbose {
-		fmt.Fprintln(os.Stderr, "Launching", burp.Name(), cfg.Product.Version)
+		fmt.Fprintln(os.Stderr, "Launching", prod.Name(), cfg.Product.Version)
 	}
 
 	exec := app.FSExecutor{DryRun: dryRun}
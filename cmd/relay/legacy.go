@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+// offerRelocation moves relay directories left where an older release put
+// them to their current locations, asking first unless yes is set.
+func offerRelocation(cfg config.Config, p paths.Paths, yes bool) error {
+	found, err := paths.FindLegacy(p, pathOptions(cfg))
+	if err != nil {
+		return fmt.Errorf("check legacy paths: %w", err)
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	fmt.Println("Found relay directories at locations this version no longer uses:")
+	for _, r := range found {
+		fmt.Printf("  %-8s %s -> %s\n", r.Name, r.From, r.To)
+	}
+
+	if dryRun {
+		for _, r := range found {
+			fmt.Printf("[dry-run] move: %s -> %s\n", r.From, r.To)
+		}
+		return nil
+	}
+
+	if !yes {
+		fmt.Print("Move them to the new locations? [Y/n]: ")
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if err != nil || (response != "" && response != "y" && response != "yes") {
+			fmt.Println("Leaving them in place; relay will use the new locations.")
+			return nil
+		}
+	}
+
+	for _, r := range found {
+		if err := paths.Relocate(r); err != nil {
+			return err
+		}
+		fmt.Printf("Moved %s to %s\n", r.From, r.To)
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package main
+
+// Products register themselves with the product registry.
+import (
+	_ "github.com/sdmrf/relay/internal/product/burpsuite"
+	_ "github.com/sdmrf/relay/internal/product/zap"
+)
@@ -1,55 +1,83 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/sdmrf/relay/internal/app"
+	"github.com/sdmrf/relay/internal/downloader"
+	"github.com/sdmrf/relay/internal/launcher"
 	"github.com/sdmrf/relay/internal/paths"
-	"github.com/sdmrf/relay/internal/product/burpsuite"
-	"github.com/sdmrf/relay/pkg/config"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/spf13/cobra"
 )
 
+var (
+	removeYes      bool // Skip the confirmation prompt
+	removeKeepData bool
+	removePurge    bool
+)
+
 var removeCmd = &cobra.Command{
 	Use:   "remove",
-	Short: "Remove Burp Suite",
-	Long:  `Uninstall Burp Suite and remove related files. Configuration is preserved.`,
-	RunE:  runRemove,
+	Short: "Remove the installed product",
+	Long: `Uninstall the product and remove related files. Configuration is preserved,
+and so is the data directory unless --purge is given.`,
+	RunE: runRemove,
 }
 
 func init() {
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "skip the confirmation prompt")
+	removeCmd.Flags().BoolVar(&removeKeepData, "keep-data", true, "keep the data directory")
+	removeCmd.Flags().BoolVar(&removePurge, "purge", false, "also delete the data directory")
+	removeCmd.MarkFlagsMutuallyExclusive("keep-data", "purge")
 	rootCmd.AddCommand(removeCmd)
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load(cfgFile)
+	cfg, p, err := loadContext()
 	if err != nil {
-		return fmt.Errorf("load config: %w", err)
+		return err
 	}
 
-	p, err := paths.Resolve(paths.Options{
-		Layout:      paths.Layout(cfg.Layout.Mode),
-		InstallHint: cfg.Paths.Install,
-		DataHint:    cfg.Paths.Data,
-		BinHint:     cfg.Paths.Bin,
-	})
-	if err != nil {
-		return fmt.Errorf("resolve paths: %w", err)
+	if err := offerRelocation(cfg, p, removeYes); err != nil {
+		return err
 	}
 
-	burp, err := burpsuite.New(cfg, p)
+	prod, err := product.New(cfg, p)
 	if err != nil {
 		return fmt.Errorf("create product: %w", err)
 	}
 
-	removePlan, err := burp.ResolveRemove()
+	removePlan, err := prod.ResolveRemove()
 	if err != nil {
 		return fmt.Errorf("resolve remove: %w", err)
 	}
+	removePlan.Purge = removePurge || !removeKeepData
+	if launchPlan, err := prod.ResolveLaunch(); err == nil {
+		if gen, err := launcher.New(launchPlan, ""); err == nil {
+			removePlan.Shortcut = removeShortcut(shortcutName(prod.Name(), cfg.Product.Edition, cfg.InstallName), gen.Path())
+		}
+	}
+
+	if !dryRun && !removeYes {
+		ok, err := confirmRemove(removePlan)
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	if err := autoBackup(cmd, prod, cfg.Backup.Auto); err != nil {
+		return err
+	}
 
 	if verbose {
-		fmt.Fprintln(os.Stderr, "Removing", burp.Name())
+		fmt.Fprintln(os.Stderr, "Removing", prod.Name())
 	}
 
 	exec := app.FSExecutor{DryRun: dryRun}
@@ -63,3 +91,71 @@ func runRemove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// confirmRemove lists what removePlan deletes and keeps, and asks before
+// going ahead. It reports false when there is nothing to delete.
+func confirmRemove(p plan.RemovePlan) (bool, error) {
+	for _, dir := range p.Targets() {
+		if err := paths.CheckOwned(dir); err != nil {
+			return false, err
+		}
+	}
+
+	var targets []string
+	for _, dir := range p.Targets() {
+		if _, err := os.Stat(dir); err == nil {
+			targets = append(targets, dir)
+		}
+	}
+	if len(targets) == 0 && p.Shortcut == nil {
+		fmt.Println("Nothing to remove")
+		return false, nil
+	}
+
+	fmt.Println("This will delete:")
+	for _, dir := range targets {
+		size := dirSize(dir)
+		if !p.Purge && p.Paths.DataDir != dir && paths.Within(p.Paths.DataDir, dir) {
+			size -= dirSize(p.Paths.DataDir)
+		}
+		fmt.Printf("  %-60s %s\n", dir, downloader.FormatBytes(size))
+	}
+	if p.Shortcut != nil {
+		fmt.Printf("  %s (desktop shortcut)\n", shortcutPath(p.Shortcut.Name))
+	}
+	if !p.Purge {
+		if _, err := os.Stat(p.Paths.DataDir); err == nil {
+			fmt.Printf("Keeping data in %s (use --purge to delete it)\n", p.Paths.DataDir)
+		}
+	}
+
+	fmt.Print("Continue? [y/N]: ")
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && response == "" {
+		return false, fmt.Errorf("read response: %w", err)
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		fmt.Println("Aborted")
+		return false, nil
+	}
+	return true, nil
+}
+
+// dirSize returns the total size of the regular files under dir.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
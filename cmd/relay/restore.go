@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sdmrf/relay/internal/app"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/spf13/cobra"
+)
+
+var restoreYes bool // Skip the confirmation prompt
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a backup made by relay backup",
+	Long: `Validate a backup archive and restore its contents to this machine's
+locations. Files in the backup replace existing ones; other files are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "skip the confirmation prompt")
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	cfg, p, err := loadContext()
+	if err != nil {
+		return err
+	}
+
+	prod, err := product.New(cfg, p)
+	if err != nil {
+		return fmt.Errorf("create product: %w", err)
+	}
+
+	backuper, ok := prod.(product.Backuper)
+	if !ok {
+		return fmt.Errorf("%s does not support backups", prod.Name())
+	}
+
+	restorePlan, err := backuper.ResolveRestore(args[0])
+	if err != nil {
+		return fmt.Errorf("resolve restore: %w", err)
+	}
+
+	if !dryRun && !restoreYes {
+		tmpDir, err := os.MkdirTemp("", "relay-restore-")
+		if err != nil {
+			return fmt.Errorf("create temp dir: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		// The archive is validated once and restored from where it was extracted
+		m, err := app.ReadBackup(args[0], prod.Name(), tmpDir)
+		if err != nil {
+			return err
+		}
+		if ok, err := confirmRestore(m, restorePlan.Targets); err != nil || !ok {
+			return err
+		}
+		restorePlan.Manifest, restorePlan.Extracted = &m, tmpDir
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), restorePlan); err != nil {
+		return fmt.Errorf("execute restore: %w", err)
+	}
+
+	return nil
+}
+
+// confirmRestore lists where each component of the backup will be
+// restored, and asks before going ahead.
+func confirmRestore(m plan.BackupManifest, targets []plan.BackupSource) (bool, error) {
+	paths := map[string]string{}
+	for _, t := range targets {
+		paths[t.Name] = t.Path
+	}
+
+	fmt.Printf("Backup from %s (%s %s) will overwrite:\n", m.Created.Local().Format("2006-01-02 15:04"), m.Product, m.Edition)
+	for _, c := range m.Components {
+		target := paths[c.Name]
+		if target == "" {
+			target = "(skipped: not used on this system)"
+		}
+		fmt.Printf("  %-10s %s\n", c.Name, target)
+	}
+
+	fmt.Print("Continue? [y/N]: ")
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && response == "" {
+		return false, fmt.Errorf("read response: %w", err)
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		fmt.Println("Aborted")
+		return false, nil
+	}
+	return true, nil
+}
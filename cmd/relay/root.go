@@ -3,19 +3,28 @@ package main
 import (
 	"os"
 
+	"github.com/sdmrf/relay/internal/downloader"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	dryRun  bool
-	verbose bool
+	cfgFile     string
+	dryRun      bool
+	verbose     bool
+	offline     bool
+	setFlags    []string
+	installName string // --install: the named install to act on
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "relay",
 	Short: "A modern CLI for managing Burp Suite installations",
-	Long:  `relay is a command-line tool for installing, launching, and managing Burp Suite.`,
+	Long:  `relay is a command-line tool for installing, launching, and managing Burp Suite and OWASP ZAP.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if offline {
+			downloader.DisableNetwork()
+		}
+	},
 }
 
 func Execute() {
@@ -25,7 +34,10 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "config.yaml", "config file path")
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "config.yaml", "project config file path")
+	rootCmd.PersistentFlags().StringArrayVar(&setFlags, "set", nil, "override a config value (key=value, repeatable)")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "preview actions without executing")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "skip network checks and fail instead of using the network")
+	rootCmd.PersistentFlags().StringVar(&installName, "install", "", "named install from the installs list to act on")
 }
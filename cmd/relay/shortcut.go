@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// shortcutName names the desktop shortcut of an install: the product's
+// display name, and the install name when there is one.
+func shortcutName(product, edition, install string) string {
+	name := product
+	switch {
+	case product == "burpsuite" && edition == "community":
+		name = "Burp Suite Community Edition"
+	case product == "burpsuite":
+		name = "Burp Suite Professional"
+	case product == "zap":
+		name = "OWASP ZAP"
+	}
+	if install != "" {
+		name += " (" + install + ")"
+	}
+	return name
+}
+
+// shortcutPath returns where the named shortcut lives.
+func shortcutPath(name string) string {
+	path, _, _ := findShortcut(name, "")
+	return path
+}
+
+// findShortcut looks for the named shortcut. It returns its path, or ""
+// when this OS has no desktop shortcuts, and whether it exists and whether
+// it runs launcherPath, which makes it relay's.
+func findShortcut(name, launcherPath string) (path string, exists, owned bool) {
+	sc, err := launcher.NewShortcut(launcher.ShortcutConfig{Name: name})
+	if err != nil {
+		return "", false, false
+	}
+	path = sc.Path()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return path, false, false
+	}
+	// A macOS app bundle runs a script inside it
+	target := path
+	if info.IsDir() {
+		target = filepath.Join(path, "Contents", "MacOS", "launcher")
+	}
+	data, err := os.ReadFile(target)
+	return path, true, err == nil && strings.Contains(string(data), launcherPath)
+}
+
+// removeShortcut returns the plan that deletes the named shortcut, or nil
+// when there is none or it isn't relay's.
+func removeShortcut(name, launcherPath string) *plan.ShortcutPlan {
+	if _, _, owned := findShortcut(name, launcherPath); !owned {
+		return nil
+	}
+	return &plan.ShortcutPlan{Name: name, Launcher: launcherPath, Remove: true}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sdmrf/relay/internal/app"
+	"github.com/sdmrf/relay/internal/diagnostics"
+	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/pkg/config"
+	"github.com/sdmrf/relay/pkg/version"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	supportOutput string   // Archive to write
+	supportRedact []string // Extra patterns to redact
+	supportYes    bool     // Skip the confirmation prompt
+)
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collect diagnostics into a redacted archive",
+	Long: `Write a zip to attach to a bug report. It holds the doctor report as JSON,
+the effective config, resolved paths, discovered Java installations, version
+and ownership markers, the install manifest, the launcher script and the end
+of the product's logs. relay keeps no log of its own.
+
+Passwords, tokens, credentials in URLs and your home directory are redacted
+from every file. Add your own regular expressions with --redact, such as
+internal hostnames. The file listing is shown before anything is written.`,
+	Args: cobra.NoArgs,
+	RunE: runSupportBundle,
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVarP(&supportOutput, "output", "o", "", "archive file to write (default relay-support-<time>.zip)")
+	supportBundleCmd.Flags().StringArrayVar(&supportRedact, "redact", nil, "also redact matches of this regular expression (repeatable)")
+	supportBundleCmd.Flags().BoolVarP(&supportYes, "yes", "y", false, "write the bundle without asking")
+	rootCmd.AddCommand(supportBundleCmd)
+}
+
+func runSupportBundle(cmd *cobra.Command, args []string) error {
+	// Fail on a bad pattern before doing any work
+	if _, err := app.CompileRedactions(supportRedact); err != nil {
+		return err
+	}
+
+	archive := supportOutput
+	if archive == "" {
+		archive = fmt.Sprintf("relay-support-%s.zip", time.Now().Format("20060102-150405"))
+	}
+	archive, err := filepath.Abs(archive)
+	if err != nil {
+		return fmt.Errorf("resolve output path: %w", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("get home directory: %w", err)
+	}
+
+	env := diagnosticsEnv()
+	files, err := supportFiles(cmd, env)
+	if err != nil {
+		return err
+	}
+
+	bundle := plan.SupportBundlePlan{
+		Archive: archive,
+		Files:   presentFiles(files),
+		Redact:  supportRedact,
+		Home:    home,
+	}
+
+	printBundleFiles(bundle.Files)
+	fmt.Println()
+
+	if !dryRun && !supportYes {
+		fmt.Printf("Write %s? [y/N]: ", archive)
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && response == "" {
+			return fmt.Errorf("read response: %w", err)
+		}
+
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), bundle); err != nil {
+		return fmt.Errorf("execute support bundle: %w", err)
+	}
+
+	return nil
+}
+
+// supportFiles gathers the bundle's generated reports and the files it
+// copies. Loading and resolving errors are recorded in the bundle, since
+// they are often what the bug report is about.
+func supportFiles(cmd *cobra.Command, env diagnostics.Env) ([]plan.BundleFile, error) {
+	report, err := diagnostics.Default.Run(cmd.Context(), env, diagnostics.Selection{})
+	if err != nil {
+		return nil, err
+	}
+	doctor, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode doctor report: %w", err)
+	}
+
+	files := []plan.BundleFile{
+		{Name: "doctor.json", Data: append(doctor, '\n')},
+		{Name: "config.yaml", Data: effectiveConfig(env)},
+		{Name: "versions.txt", Data: versionInfo(env)},
+	}
+
+	if env.PathsErr != nil {
+		files = append(files, plan.BundleFile{Name: "paths.txt", Data: []byte(env.PathsErr.Error() + "\n")})
+		return files, nil
+	}
+	p := env.Paths
+
+	var java bytes.Buffer
+	writeJavaList(&java, env.Config, p)
+	files = append(files,
+		plan.BundleFile{Name: "paths.txt", Data: pathsInfo(p)},
+		plan.BundleFile{Name: "java.txt", Data: java.Bytes()},
+		plan.BundleFile{Name: "markers/" + product.ManifestFile, Path: filepath.Join(p.InstallDir, product.ManifestFile)},
+	)
+
+	for _, dir := range namedDirs(p) {
+		files = append(files, plan.BundleFile{
+			Name: "markers/" + dir.name + paths.OwnershipMarker,
+			Path: filepath.Join(dir.path, paths.OwnershipMarker),
+		})
+	}
+
+	if env.Product == nil {
+		return files, nil
+	}
+	if launchPlan, err := env.Product.ResolveLaunch(); err == nil {
+		if gen, err := launcher.New(launchPlan, ""); err == nil {
+			files = append(files, plan.BundleFile{Name: "launcher/" + filepath.Base(gen.Path()), Path: gen.Path()})
+		}
+	}
+	if filer, ok := env.Product.(product.SupportFiler); ok {
+		files = append(files, filer.SupportFiles()...)
+	}
+
+	return files, nil
+}
+
+// effectiveConfig encodes the merged config with the layer each value
+// came from, or the error that stopped it loading.
+func effectiveConfig(env diagnostics.Env) []byte {
+	var buf bytes.Buffer
+	if env.ConfigErr != nil {
+		fmt.Fprintf(&buf, "# Config failed to load; showing defaults\n# %s\n\n", env.ConfigErr)
+	}
+
+	data, err := yaml.Marshal(env.Config)
+	if err != nil {
+		fmt.Fprintf(&buf, "# encode config: %v\n", err)
+		return buf.Bytes()
+	}
+	buf.Write(data)
+
+	if env.ConfigErr == nil {
+		buf.WriteString("\n# Origins\n")
+		for _, key := range config.Keys() {
+			fmt.Fprintf(&buf, "# %s: %s\n", key, env.Origins[key])
+		}
+	}
+	return buf.Bytes()
+}
+
+// versionInfo describes relay, the host and the installed releases.
+func versionInfo(env diagnostics.Env) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "relay %s (commit %s, built %s)\n", version.Version, version.Commit, version.BuildDate)
+	fmt.Fprintf(&buf, "os: %s/%s\n", goruntime.GOOS, goruntime.GOARCH)
+	fmt.Fprintf(&buf, "product: %s %s\n", env.Config.Product.Name, env.Config.Product.Edition)
+
+	if env.PathsErr == nil {
+		jre, err := runtime.ReadJREMarker(env.Paths.InstallDir)
+		switch {
+		case err != nil:
+			fmt.Fprintf(&buf, "bundled jre: %v\n", err)
+		case jre == "":
+			fmt.Fprintln(&buf, "bundled jre: not installed")
+		default:
+			fmt.Fprintf(&buf, "bundled jre: %s\n", jre)
+		}
+	}
+
+	if path, err := runtime.ResolveJavaPath(env.Java); err != nil {
+		fmt.Fprintf(&buf, "java (%s): %v\n", env.Java.Strategy, err)
+	} else {
+		fmt.Fprintf(&buf, "java (%s): %s\n", env.Java.Strategy, path)
+	}
+	return buf.Bytes()
+}
+
+type namedDir struct {
+	name string
+	path string
+}
+
+// namedDirs lists the resolved directories in a fixed order.
+func namedDirs(p paths.Paths) []namedDir {
+	return []namedDir{
+		{"install", p.InstallDir},
+		{"data", p.DataDir},
+		{"bin", p.BinDir},
+		{"config", p.ConfigDir},
+		{"cache", p.CacheDir},
+		{"state", p.StateDir},
+		{"backup", p.BackupDir},
+	}
+}
+
+func pathsInfo(p paths.Paths) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "layout: %s\n", p.Layout)
+	fmt.Fprintf(&buf, "shared: %t\n", p.Shared)
+	for _, dir := range namedDirs(p) {
+		fmt.Fprintf(&buf, "%s: %s\n", dir.name, dir.path)
+	}
+	return buf.Bytes()
+}
+
+// presentFiles drops bundle files whose source doesn't exist.
+func presentFiles(files []plan.BundleFile) []plan.BundleFile {
+	var present []plan.BundleFile
+	for _, f := range files {
+		if f.Data == nil {
+			if info, err := os.Stat(f.Path); err != nil || info.IsDir() {
+				continue
+			}
+		}
+		present = append(present, f)
+	}
+	return present
+}
+
+// printBundleFiles lists what the bundle will contain and where each
+// entry comes from.
+func printBundleFiles(files []plan.BundleFile) {
+	fmt.Println("The support bundle will contain:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, f := range files {
+		source := "generated"
+		if f.Data == nil {
+			source = f.Path
+			if f.Tail > 0 {
+				source += fmt.Sprintf(" (last %d KiB)", f.Tail>>10)
+			}
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", f.Name, source)
+	}
+	w.Flush()
+	fmt.Println()
+	fmt.Println("Secrets and --redact matches are replaced with [REDACTED], and your home directory with ~.")
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sdmrf/relay/internal/app"
+	"github.com/sdmrf/relay/internal/diagnostics"
+	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncPrune bool // Remove named installs that are no longer declared
+	syncYes   bool // Apply without asking
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Bring the installs in line with the config",
+	Long: `Compare what is installed with the config and apply only the changes needed:
+install missing products, reinstall those whose product, edition or version
+differs from what the install manifest records, download the bundled JRE the
+Java strategy needs, generate missing or stale launchers, and add or remove
+the desktop shortcut per desktop.shortcut. With --prune, named installs that
+are no longer declared are removed. Burp extensions and profiles are not
+managed by relay and are left alone.
+
+The changes are listed, diff style, before anything is applied. Commit the
+config and run 'relay sync' on each machine to get the same setup.`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncPrune, "prune", false, "remove named installs that are no longer declared")
+	syncCmd.Flags().BoolVarP(&syncYes, "yes", "y", false, "apply the changes without asking")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if syncPrune && installName != "" {
+		return fmt.Errorf("--prune cannot be combined with --install")
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	names := cfg.InstallNames()
+	switch {
+	case installName != "":
+		names = []string{installName}
+	case len(names) == 0:
+		names = []string{""}
+	}
+
+	var syncPlan plan.SyncPlan
+	var unchanged []string
+	for _, name := range names {
+		steps, same, err := syncInstall(cmd.Context(), name)
+		if err != nil {
+			if name != "" {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			return err
+		}
+		syncPlan.Steps = append(syncPlan.Steps, steps...)
+		unchanged = append(unchanged, same...)
+	}
+
+	if syncPrune {
+		steps, err := pruneSteps(cfg.InstallNames())
+		if err != nil {
+			return err
+		}
+		syncPlan.Steps = append(syncPlan.Steps, steps...)
+	}
+
+	printSyncSummary(syncPlan.Steps, unchanged)
+	if len(syncPlan.Steps) == 0 {
+		fmt.Println("Nothing to do")
+		return nil
+	}
+	fmt.Println()
+
+	if !dryRun && !syncYes {
+		fmt.Printf("Apply %d change(s)? [y/N]: ", len(syncPlan.Steps))
+		response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && response == "" {
+			return fmt.Errorf("read response: %w", err)
+		}
+
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	exec := app.FSExecutor{DryRun: dryRun}
+	if err := exec.Execute(cmd.Context(), syncPlan); err != nil {
+		return fmt.Errorf("execute sync: %w", err)
+	}
+
+	if !dryRun {
+		fmt.Println("Sync complete")
+	}
+	return nil
+}
+
+// syncInstall compares the named install with its config. It returns the
+// steps that converge it, and a line for each part already in line.
+func syncInstall(ctx context.Context, name string) ([]plan.SyncStep, []string, error) {
+	cfg, _, err := loadConfigFor(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := resolvePaths(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	prod, err := product.New(cfg, p)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create product: %w", err)
+	}
+
+	installPlan, err := prod.ResolveInstall()
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve install: %w", err)
+	}
+	launchPlan, err := prod.ResolveLaunch()
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve launch: %w", err)
+	}
+
+	var steps []plan.SyncStep
+	var unchanged []string
+	step := func(change plan.SyncChange, summary string, pl plan.Plan) {
+		steps = append(steps, plan.SyncStep{Install: name, Change: change, Summary: summary, Plan: pl})
+	}
+	same := func(summary string) {
+		if name != "" {
+			summary = name + ": " + summary
+		}
+		unchanged = append(unchanged, summary)
+	}
+
+	javaOpts := javaOptions(cfg, p)
+	needsJRE, err := runtime.NeedsJRE(javaOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("check java: %w", err)
+	}
+	var jre *plan.JREArtifact
+	if needsJRE {
+		if jre, err = resolveJREArtifact(ctx, cfg, p.InstallDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	want := product.Manifest{Product: installPlan.Product, Edition: installPlan.Edition, Version: installPlan.Version}
+	if want.Version == "" {
+		want.Version = "latest"
+	}
+	have, manifestErr := product.ReadManifest(p.InstallDir)
+	current, markerErr := product.ReadVersionMarker(p.InstallDir)
+	_, jarErr := os.Stat(launchPlan.Jar)
+
+	switch drift := installDrift(have, manifestErr, current, want); {
+	case drift != "" && (manifestErr == nil || markerErr == nil):
+		if backuper, ok := prod.(product.Backuper); ok && cfg.Backup.Auto {
+			backupPlan, err := backuper.ResolveBackup(time.Now())
+			if err != nil {
+				return nil, nil, fmt.Errorf("resolve backup: %w", err)
+			}
+			step(plan.SyncAdd, "backup "+backupPlan.Archive, backupPlan)
+		}
+		if updatePlan, err := prod.ResolveUpdate(); err == nil {
+			installPlan.Replaces = updatePlan.Replaces
+		}
+		step(plan.SyncModify, drift, installPlan)
+	case jarErr != nil:
+		step(plan.SyncAdd, describeInstall(want), installPlan)
+	default:
+		same(describeInstall(want))
+	}
+
+	installedJRE, err := runtime.ReadJREMarker(p.InstallDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read JRE marker: %w", err)
+	}
+	switch {
+	case jre != nil:
+		change := plan.SyncAdd
+		if runtime.HasBundledJRE(p.InstallDir) {
+			change = plan.SyncModify
+		}
+		step(change, fmt.Sprintf("jre %s -> %s", displayVersion(installedJRE), jre.Version), plan.RuntimePlan{
+			Action:         plan.RuntimeInstall,
+			CurrentVersion: installedJRE,
+			TargetVersion:  jre.Version,
+			Paths:          plan.FromResolved(p),
+			JREArtifact:    jre,
+		})
+	case installedJRE != "":
+		same("jre " + installedJRE)
+	}
+
+	// The launcher points at the JAR and Java, which the steps above change
+	javaPath, _ := runtime.ResolveJavaPath(javaOpts)
+	gen, err := launcher.New(launchPlan, javaPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create launcher: %w", err)
+	}
+	_, launcherErr := os.Stat(gen.Path())
+	check := diagnostics.CheckLauncher(gen.Path(), javaPath)
+	switch {
+	case launcherErr != nil:
+		step(plan.SyncAdd, "launcher "+gen.Path(), plan.RepairPlan{
+			Product: prod.Name(),
+			Paths:   plan.FromResolved(p),
+			Dirs:    []string{filepath.Dir(gen.Path())},
+			Launch:  &launchPlan,
+		})
+	case check.Status != diagnostics.StatusOK || len(steps) > 0:
+		step(plan.SyncModify, "launcher "+gen.Path(), plan.RepairPlan{Product: prod.Name(), Paths: plan.FromResolved(p), Launch: &launchPlan})
+	default:
+		same("launcher " + gen.Path())
+	}
+
+	// The shortcut runs the launcher; it is refreshed along with it
+	title := shortcutName(installPlan.Product, installPlan.Edition, cfg.InstallName)
+	add := plan.ShortcutPlan{Name: title, Launcher: gen.Path()}
+	if path, exists, owned := findShortcut(title, gen.Path()); path != "" {
+		switch {
+		case cfg.Desktop.Shortcut && !exists:
+			step(plan.SyncAdd, "shortcut "+path, add)
+		case cfg.Desktop.Shortcut && !owned:
+			return nil, nil, fmt.Errorf("shortcut %s exists but doesn't run relay's launcher; rename or delete it", path)
+		case cfg.Desktop.Shortcut && len(steps) > 0:
+			step(plan.SyncModify, "shortcut "+path, add)
+		case cfg.Desktop.Shortcut:
+			same("shortcut " + path)
+		case owned:
+			step(plan.SyncDelete, "shortcut "+path, plan.ShortcutPlan{Name: title, Launcher: gen.Path(), Remove: true})
+		}
+	}
+
+	return steps, unchanged, nil
+}
+
+// installDrift describes how the install differs from want, or returns ""
+// when it matches. A pinned version is checked against the version
+// marker, which records what was downloaded; "latest" isn't checked
+// for newer releases.
+func installDrift(have product.Manifest, manifestErr error, marker string, want product.Manifest) string {
+	if manifestErr != nil {
+		have = product.Manifest{Product: want.Product, Version: displayVersion(marker) + " (no manifest)"}
+	} else if have.Version != "latest" && marker != "" {
+		have.Version = marker
+	}
+
+	if have.Product == want.Product && have.Edition == want.Edition && have.Version == want.Version {
+		return ""
+	}
+	return describeInstall(have) + " -> " + describeInstall(want)
+}
+
+// describeInstall names the product, edition and version of m.
+func describeInstall(m product.Manifest) string {
+	return strings.Join(strings.Fields(m.Product+" "+m.Edition+" "+m.Version), " ")
+}
+
+// pruneSteps removes named installs relay created under the default
+// install directory that the config no longer declares. Installs with
+// their own paths.install are not found.
+func pruneSteps(declared []string) ([]plan.SyncStep, error) {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	root, err := resolvePaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(root.InstallDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list installs: %w", err)
+	}
+
+	var steps []plan.SyncStep
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || slices.Contains(declared, name) {
+			continue
+		}
+		dir := filepath.Join(root.InstallDir, name)
+		if !fileExists(filepath.Join(dir, product.VersionMarkerFile)) || !fileExists(filepath.Join(dir, paths.OwnershipMarker)) {
+			continue
+		}
+
+		cfg.InstallName = name
+		p, err := resolvePaths(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		removePlan := plan.RemovePlan{Paths: plan.FromResolved(p)}
+		if m, err := product.ReadManifest(dir); err == nil {
+			removePlan.Product = m.Product
+			if gen, err := launcher.New(plan.LaunchPlan{Product: m.Product, Paths: removePlan.Paths}, ""); err == nil {
+				removePlan.Shortcut = removeShortcut(shortcutName(m.Product, m.Edition, name), gen.Path())
+			}
+		}
+		steps = append(steps, plan.SyncStep{
+			Install: name,
+			Change:  plan.SyncDelete,
+			Summary: "remove " + p.InstallDir,
+			Plan:    removePlan,
+		})
+	}
+	return steps, nil
+}
+
+// printSyncSummary lists the changes, then what is already in line.
+func printSyncSummary(steps []plan.SyncStep, unchanged []string) {
+	for _, s := range steps {
+		title := s.Summary
+		if s.Install != "" {
+			title = s.Install + ": " + title
+		}
+		fmt.Printf("%s %s\n", s.Change, title)
+	}
+	if verbose {
+		for _, line := range unchanged {
+			fmt.Printf("= %s\n", line)
+		}
+	}
+}
@@ -5,50 +5,52 @@ import (
 	"os"
 
 	"github.com/sdmrf/relay/internal/app"
-	"github.com/sdmrf/relay/internal/paths"
-	"github.com/sdmrf/relay/internal/product/burpsuite"
-	"github.com/sdmrf/relay/pkg/config"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/spf13/cobra"
 )
 
 var (
 	updateForce bool
+	updateAll   bool // Update every declared install
 )
 
 var updateCmd = &cobra.Command{
 	Use:   "update",
-	Short: "Update Burp Suite to the latest version",
-	Long:  `Check for updates and download the latest version of Burp Suite.`,
+	Short: "Update the installed product",
+	Long:  `Check for updates and download the configured version of the product.`,
 	RunE:  runUpdate,
 }
 
 func init() {
 	updateCmd.Flags().BoolVarP(&updateForce, "force", "f", false, "force update even if already at latest version")
+	updateCmd.Flags().BoolVar(&updateAll, "all", false, "update every install in the installs list")
 	rootCmd.AddCommand(updateCmd)
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load(cfgFile)
-	if err != nil {
-		return fmt.Errorf("load config: %w", err)
+	if updateAll {
+		return forEachInstall(func() error { return updateOne(cmd) })
 	}
+	return updateOne(cmd)
+}
 
-	p, err := paths.Resolve(paths.Options{
-		Layout:      paths.Layout(cfg.Layout.Mode),
-		InstallHint: cfg.Paths.Install,
-		DataHint:    cfg.Paths.Data,
-		BinHint:     cfg.Paths.Bin,
-	})
+// updateOne updates the selected install.
+func updateOne(cmd *cobra.Command) error {
+	cfg, p, err := loadContext()
 	if err != nil {
-		return fmt.Errorf("resolve paths: %w", err)
+		return err
+	}
+
+	if err := offerRelocation(cfg, p, false); err != nil {
+		return err
 	}
 
-	burp, err := burpsuite.New(cfg, p)
+	prod, err := product.New(cfg, p)
 	if err != nil {
 		return fmt.Errorf("create product: %w", err)
 	}
 
-	updatePlan, err := burp.ResolveUpdate()
+	updatePlan, err := prod.ResolveUpdate()
 	if err != nil {
 		// No installed version - suggest running install
 		fmt.Fprintln(os.Stderr, "No installation found. Run 'relay install' first.")
@@ -63,7 +65,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	// For now, always proceed with update (downloads latest)
 	// Future: check actual version from server
 	if !updateForce && updatePlan.TargetVersion != "latest" {
-		cmp := burpsuite.CompareVersions(updatePlan.CurrentVersion, updatePlan.TargetVersion)
+		cmp := product.CompareVersions(updatePlan.CurrentVersion, updatePlan.TargetVersion)
 		if cmp >= 0 {
 			fmt.Println("Already at latest version:", updatePlan.CurrentVersion)
 			return nil
@@ -71,28 +73,25 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Updating %s from %s to %s...\n",
-		burp.Name(), updatePlan.CurrentVersion, updatePlan.TargetVersion)
+		prod.Name(), updatePlan.CurrentVersion, updatePlan.TargetVersion)
+
+	if err := autoBackup(cmd, prod, cfg.Backup.Auto); err != nil {
+		return err
+	}
 
 	// Use install plan for the actual download
-	installPlan, err := burp.ResolveInstall()
+	installPlan, err := prod.ResolveInstall()
 	if err != nil {
 		return fmt.Errorf("resolve install: %w", err)
 	}
+	installPlan.Replaces = updatePlan.Replaces
 
 	exec := app.FSExecutor{DryRun: dryRun}
 	if err := exec.Execute(cmd.Context(), installPlan); err != nil {
 		return fmt.Errorf("execute update: %w", err)
 	}
 
-	// Write version marker
 	if !dryRun {
-		version := updatePlan.TargetVersion
-		if version == "latest" {
-			version = "unknown" // Will be determined when we fetch actual version
-		}
-		if err := burpsuite.WriteVersionMarker(p.InstallDir, version); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to write version marker: %v\n", err)
-		}
 		fmt.Println("Update complete")
 	}
 
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sdmrf/relay/internal/downloader"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// manifestName is the archive entry describing a backup.
+const manifestName = "manifest.json"
+
+// manifestFormat is the backup archive format this release writes.
+const manifestFormat = 1
+
+// execBackup archives the plan's sources that exist, with a manifest.
+func (e FSExecutor) execBackup(p plan.BackupPlan) error {
+	m := plan.BackupManifest{Format: manifestFormat, Created: time.Now().UTC(), Product: p.Product, Edition: p.Edition}
+	var sources []downloader.ArchiveSource
+	for _, src := range p.Sources {
+		info, err := os.Stat(src.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("back up %s: %w", src.Name, err)
+		}
+
+		m.Components = append(m.Components, plan.BackupComponent{Name: src.Name, Path: src.Path, Dir: info.IsDir()})
+		sources = append(sources, downloader.ArchiveSource{Name: src.Name, Path: src.Path})
+	}
+	if len(m.Components) == 0 {
+		return fmt.Errorf("nothing to back up: none of the Burp or relay state directories exist")
+	}
+
+	if e.DryRun {
+		for _, c := range m.Components {
+			fmt.Printf("[dry-run] back up %s: %s\n", c.Name, c.Path)
+		}
+		fmt.Println("[dry-run] write:", p.Archive)
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	sources = append([]downloader.ArchiveSource{{Name: manifestName, Data: data}}, sources...)
+
+	if err := os.MkdirAll(filepath.Dir(p.Archive), 0o700); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+	if err := downloader.CreateTarGz(p.Archive, sources); err != nil {
+		return err
+	}
+
+	fmt.Println("Backup written to", p.Archive)
+	return nil
+}
+
+// execRestore validates the archive and copies each component over its
+// target. Files missing from the backup are left alone, as are components
+// this system has no place for, such as Java preferences from another OS.
+func (e FSExecutor) execRestore(p plan.RestorePlan) error {
+	if p.Manifest == nil {
+		tmpDir, err := os.MkdirTemp("", "relay-restore-")
+		if err != nil {
+			return fmt.Errorf("create temp dir: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		m, err := ReadBackup(p.Archive, p.Product, tmpDir)
+		if err != nil {
+			return err
+		}
+		p.Manifest, p.Extracted = &m, tmpDir
+	}
+
+	targets := map[string]string{}
+	for _, t := range p.Targets {
+		targets[t.Name] = t.Path
+	}
+
+	for _, c := range p.Manifest.Components {
+		target, ok := targets[c.Name]
+		src := filepath.Join(p.Extracted, c.Name)
+
+		if !ok || target == "" {
+			fmt.Printf("Skipped %s: not used on this system\n", c.Name)
+			continue
+		}
+		if e.DryRun {
+			fmt.Printf("[dry-run] restore %s: %s\n", c.Name, target)
+			continue
+		}
+
+		// The data directory is relay's and gets its ownership marker
+		if target == p.Paths.DataDir {
+			if err := e.claim(p.Paths, []string{target}); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if c.Dir {
+			err = copyTree(src, target)
+		} else {
+			err = copyFile(src, target)
+		}
+		if err != nil {
+			return fmt.Errorf("restore %s: %w", c.Name, err)
+		}
+		fmt.Printf("Restored %s to %s\n", c.Name, target)
+	}
+	return nil
+}
+
+// ReadBackup extracts the backup archive into dir and validates it: the
+// manifest must be a known format for product, and the archive may only
+// hold the components the manifest lists.
+func ReadBackup(archive, product, dir string) (plan.BackupManifest, error) {
+	if err := downloader.ExtractTarGz(archive, dir); err != nil {
+		return plan.BackupManifest{}, fmt.Errorf("read backup %s: %w", archive, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return plan.BackupManifest{}, fmt.Errorf("read backup %s: no %s; not a relay backup", archive, manifestName)
+	}
+
+	var m plan.BackupManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return plan.BackupManifest{}, fmt.Errorf("read backup manifest: %w", err)
+	}
+	if m.Format != manifestFormat {
+		return plan.BackupManifest{}, fmt.Errorf("backup format %d is not supported (want %d)", m.Format, manifestFormat)
+	}
+	if m.Product != product {
+		return plan.BackupManifest{}, fmt.Errorf("backup is for %s, not %s", m.Product, product)
+	}
+
+	listed := map[string]bool{manifestName: true}
+	for _, c := range m.Components {
+		if !knownComponents[c.Name] {
+			return plan.BackupManifest{}, fmt.Errorf("backup has unknown component %q", c.Name)
+		}
+		info, err := os.Stat(filepath.Join(dir, c.Name))
+		if err != nil || info.IsDir() != c.Dir {
+			return plan.BackupManifest{}, fmt.Errorf("backup is missing component %q", c.Name)
+		}
+		listed[c.Name] = true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return plan.BackupManifest{}, err
+	}
+	for _, entry := range entries {
+		if !listed[entry.Name()] {
+			return plan.BackupManifest{}, fmt.Errorf("backup has unexpected entry %q", entry.Name())
+		}
+	}
+	return m, nil
+}
+
+// knownComponents are the component names relay writes.
+var knownComponents = map[string]bool{
+	"burp":       true,
+	"java-prefs": true,
+	"config":     true,
+	"data":       true,
+}
+
+// copyTree copies the regular files under src into dst, replacing files
+// that exist.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case info.Mode().IsRegular():
+			return copyFile(path, target)
+		default:
+			return nil
+		}
+	})
+}
+
+// copyFile copies src to dst, keeping the mode and replacing dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/downloader"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "burp", "UserConfigPro.json"), "{}")
+	writeFile(t, filepath.Join(src, "config.yaml"), "version: 3\n")
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	backup := plan.BackupPlan{Product: "burpsuite", Edition: "professional", Archive: archive, Sources: []plan.BackupSource{
+		{Name: "burp", Path: filepath.Join(src, "burp")},
+		{Name: "config", Path: filepath.Join(src, "config.yaml")},
+		{Name: "data", Path: filepath.Join(src, "missing")},
+	}}
+	if err := (FSExecutor{}).Execute(context.Background(), backup); err != nil {
+		t.Fatalf("Execute() backup error = %v", err)
+	}
+
+	m, err := ReadBackup(archive, "burpsuite", t.TempDir())
+	if err != nil {
+		t.Fatalf("ReadBackup() error = %v", err)
+	}
+	if len(m.Components) != 2 || m.Edition != "professional" {
+		t.Errorf("ReadBackup() = %+v, want burp and config of professional", m)
+	}
+
+	// Restored to another machine's locations, keeping files not in the backup
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dst, "burp", "other.json"), "kept")
+	restore := plan.RestorePlan{Product: "burpsuite", Archive: archive, Targets: []plan.BackupSource{
+		{Name: "burp", Path: filepath.Join(dst, "burp")},
+		{Name: "config", Path: filepath.Join(dst, "relay.yaml")},
+	}}
+	if err := (FSExecutor{}).Execute(context.Background(), restore); err != nil {
+		t.Fatalf("Execute() restore error = %v", err)
+	}
+
+	for path, want := range map[string]string{
+		filepath.Join(dst, "burp", "UserConfigPro.json"): "{}",
+		filepath.Join(dst, "burp", "other.json"):         "kept",
+		filepath.Join(dst, "relay.yaml"):                 "version: 3\n",
+	} {
+		got, err := os.ReadFile(path)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", path, got, err, want)
+		}
+	}
+}
+
+func TestExecRestoreUsesReadManifest(t *testing.T) {
+	// The archive is gone: only the already extracted copy can be restored
+	extracted := t.TempDir()
+	writeFile(t, filepath.Join(extracted, "config"), "version: 3\n")
+	target := filepath.Join(t.TempDir(), "relay.yaml")
+
+	p := plan.RestorePlan{
+		Product:   "burpsuite",
+		Archive:   filepath.Join(extracted, "missing.tar.gz"),
+		Targets:   []plan.BackupSource{{Name: "config", Path: target}},
+		Manifest:  &plan.BackupManifest{Format: manifestFormat, Product: "burpsuite", Components: []plan.BackupComponent{{Name: "config"}}},
+		Extracted: extracted,
+	}
+	if err := (FSExecutor{}).Execute(context.Background(), p); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, err := os.ReadFile(target); err != nil || string(got) != "version: 3\n" {
+		t.Errorf("restored config = %q, %v", got, err)
+	}
+}
+
+func TestExecRestoreSkipsComponentsWithoutTarget(t *testing.T) {
+	// Java preferences from a Linux backup have no place on macOS or Windows
+	extracted := t.TempDir()
+	writeFile(t, filepath.Join(extracted, "java-prefs", "prefs.xml"), "<map/>")
+	writeFile(t, filepath.Join(extracted, "config"), "version: 3\n")
+	target := filepath.Join(t.TempDir(), "relay.yaml")
+
+	// Nothing may land in the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cwd := t.TempDir()
+	if err := os.Chdir(cwd); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	p := plan.RestorePlan{
+		Product: "burpsuite",
+		Targets: []plan.BackupSource{{Name: "config", Path: target}},
+		Manifest: &plan.BackupManifest{Format: manifestFormat, Product: "burpsuite", Components: []plan.BackupComponent{
+			{Name: "java-prefs", Dir: true},
+			{Name: "config"},
+		}},
+		Extracted: extracted,
+	}
+	if err := (FSExecutor{}).Execute(context.Background(), p); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if got, err := os.ReadFile(target); err != nil || string(got) != "version: 3\n" {
+		t.Errorf("restored config = %q, %v", got, err)
+	}
+	if entries, _ := os.ReadDir(cwd); len(entries) != 0 {
+		t.Errorf("restore wrote %d entries to the working directory", len(entries))
+	}
+}
+
+func TestReadBackupRejects(t *testing.T) {
+	manifest := func(m plan.BackupManifest) []byte {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	config := plan.BackupComponent{Name: "config"}
+
+	tests := []struct {
+		name    string
+		entries []downloader.ArchiveSource
+		wantErr string
+	}{
+		{
+			name:    "no manifest",
+			entries: []downloader.ArchiveSource{{Name: "config", Data: []byte("x")}},
+			wantErr: "not a relay backup",
+		},
+		{
+			name:    "bad JSON",
+			entries: []downloader.ArchiveSource{{Name: manifestName, Data: []byte("{")}},
+			wantErr: "read backup manifest",
+		},
+		{
+			name:    "unknown format",
+			entries: []downloader.ArchiveSource{{Name: manifestName, Data: manifest(plan.BackupManifest{Format: 99, Product: "burpsuite"})}},
+			wantErr: "format 99 is not supported",
+		},
+		{
+			name:    "other product",
+			entries: []downloader.ArchiveSource{{Name: manifestName, Data: manifest(plan.BackupManifest{Format: manifestFormat, Product: "zap"})}},
+			wantErr: "backup is for zap",
+		},
+		{
+			name: "unknown component",
+			entries: []downloader.ArchiveSource{
+				{Name: manifestName, Data: manifest(plan.BackupManifest{Format: manifestFormat, Product: "burpsuite", Components: []plan.BackupComponent{{Name: "etc"}}})},
+				{Name: "etc", Data: []byte("x")},
+			},
+			wantErr: `unknown component "etc"`,
+		},
+		{
+			name:    "missing component",
+			entries: []downloader.ArchiveSource{{Name: manifestName, Data: manifest(plan.BackupManifest{Format: manifestFormat, Product: "burpsuite", Components: []plan.BackupComponent{config}})}},
+			wantErr: `missing component "config"`,
+		},
+		{
+			name: "unlisted entry",
+			entries: []downloader.ArchiveSource{
+				{Name: manifestName, Data: manifest(plan.BackupManifest{Format: manifestFormat, Product: "burpsuite"})},
+				{Name: "config", Data: []byte("x")},
+			},
+			wantErr: `unexpected entry "config"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+			if err := downloader.CreateTarGz(archive, tt.entries); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := ReadBackup(archive, "burpsuite", t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadBackup() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sdmrf/relay/internal/downloader"
 	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/paths"
 	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/internal/sysinfo"
+	"github.com/sdmrf/relay/pkg/config"
 )
 
 // FSExecutor executes plans by performing filesystem operations.
@@ -29,6 +34,20 @@ func (e FSExecutor) Execute(ctx context.Context, p plan.Plan) error {
 		return e.execLaunch(ctx, p)
 	case plan.UpdatePlan:
 		return e.execUpdate(ctx, p)
+	case plan.RuntimePlan:
+		return e.execRuntime(ctx, p)
+	case plan.BackupPlan:
+		return e.execBackup(p)
+	case plan.RestorePlan:
+		return e.execRestore(p)
+	case plan.RepairPlan:
+		return e.execRepair(ctx, p)
+	case plan.SupportBundlePlan:
+		return e.execSupportBundle(p)
+	case plan.SyncPlan:
+		return e.execSync(ctx, p)
+	case plan.ShortcutPlan:
+		return e.execShortcut(p)
 	default:
 		return fmt.Errorf("unsupported plan kind: %s", p.Kind())
 	}
@@ -37,22 +56,33 @@ func (e FSExecutor) Execute(ctx context.Context, p plan.Plan) error {
 // execInstall creates required directories and downloads artifacts.
 // Uses MkdirAll for idempotency - safe to run multiple times.
 func (e FSExecutor) execInstall(ctx context.Context, p plan.InstallPlan) error {
-	dirs := []string{
-		p.Paths.InstallDir,
-		p.Paths.DataDir,
-		p.Paths.BinDir,
-		p.Paths.CacheDir,
-	}
-
-	for _, dir := range dirs {
-		if e.DryRun {
-			fmt.Println("[dry-run] mkdir:", dir)
-			continue
+	// Users without write access to a complete shared install only
+	// need their own directories
+	if p.Paths.Shared {
+		if err := checkSharedAccess(p.Paths.InstallDir, p.Group); err != nil {
+			if _, statErr := os.Stat(p.Artifact.Target); statErr != nil || p.JREArtifact != nil {
+				return err
+			}
+			if err := e.claim(p.Paths, p.Paths.Owned()); err != nil {
+				return err
+			}
+			if err := e.mkdirs([]string{p.Paths.BinDir}); err != nil {
+				return err
+			}
+			fmt.Println("Using shared installation at", p.Paths.InstallDir)
+			return nil
 		}
+	}
 
-		if err := os.MkdirAll(dir, 0o755); err != nil {
-			return fmt.Errorf("create directory %s: %w", dir, err)
-		}
+	owned := p.Paths.Owned()
+	if p.Paths.Shared {
+		owned = append([]string{p.Paths.InstallDir}, owned...)
+	}
+	if err := e.claim(p.Paths, owned); err != nil {
+		return err
+	}
+	if err := e.mkdirs([]string{p.Paths.BinDir}); err != nil {
+		return err
 	}
 
 	dl := downloader.HTTPDownloader{
@@ -68,21 +98,124 @@ func (e FSExecutor) execInstall(ctx context.Context, p plan.InstallPlan) error {
 	}
 
 	// Download product artifact
-	artifact := downloader.Artifact{
-		Name:   p.Artifact.Name,
-		URL:    p.Artifact.URL,
-		Target: p.Artifact.Target,
+	if err := e.fetchArtifact(ctx, dl, p.Artifact); err != nil {
+		return err
+	}
+	if e.DryRun {
+		if p.Replaces != "" {
+			fmt.Println("[dry-run] rm -rf:", p.Replaces)
+		}
+		return nil
 	}
 
+	// Record the version so update and sync can tell what's installed
+	version := p.Version
+	if version == "" || version == "latest" {
+		version = "unknown"
+	}
+	if err := product.WriteVersionMarker(p.Paths.InstallDir, version); err != nil {
+		return fmt.Errorf("write version marker: %w", err)
+	}
+	if err := writeManifest(p); err != nil {
+		return err
+	}
+
+	// The release this one supersedes is only deleted once it's in place
+	if p.Replaces != "" && p.Replaces != p.Paths.InstallDir && paths.Within(p.Replaces, p.Paths.InstallDir) {
+		if err := os.RemoveAll(p.Replaces); err != nil {
+			return fmt.Errorf("remove previous release %s: %w", p.Replaces, err)
+		}
+	}
+
+	if p.Paths.Shared {
+		if err := shareTree(p.Paths.InstallDir, p.Group); err != nil {
+			return fmt.Errorf("share install: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeManifest records what p installed.
+func writeManifest(p plan.InstallPlan) error {
+	m := product.Manifest{Product: p.Product, Edition: p.Edition, Version: p.Version, InstalledAt: time.Now().UTC()}
+	if m.Version == "" {
+		m.Version = "latest"
+	}
+	if err := product.WriteManifest(p.Paths.InstallDir, m); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// fetchArtifact downloads a product artifact and, for an archive, extracts
+// it and deletes the download.
+func (e FSExecutor) fetchArtifact(ctx context.Context, dl downloader.HTTPDownloader, a plan.Artifact) error {
 	if e.DryRun {
-		fmt.Println("[dry-run] download:", artifact.Name)
-		fmt.Println("[dry-run]   url:", artifact.URL)
-		fmt.Println("[dry-run]   target:", artifact.Target)
+		fmt.Println("[dry-run] download:", a.Name)
+		fmt.Println("[dry-run]   url:", a.URL)
+		fmt.Println("[dry-run]   target:", a.Target)
+		if a.ExtractTo != "" {
+			fmt.Println("[dry-run] extract to:", a.ExtractTo)
+		}
+		return nil
+	}
+
+	fmt.Println("Downloading", a.Name)
+	if err := dl.FetchWithProgress(ctx, downloader.Artifact{Name: a.Name, URL: a.URL, Target: a.Target}); err != nil {
+		return err
+	}
+	if a.ExtractTo == "" {
 		return nil
 	}
 
-	fmt.Println("Downloading", artifact.Name)
-	return dl.FetchWithProgress(ctx, artifact)
+	fmt.Println("Extracting", a.Name)
+	if err := downloader.Extract(a.Target, a.ExtractTo); err != nil {
+		return fmt.Errorf("extract %s: %w", a.Name, err)
+	}
+	if err := os.Remove(a.Target); err != nil {
+		return fmt.Errorf("remove archive: %w", err)
+	}
+	return nil
+}
+
+// claim creates dirs and marks them with an ownership marker naming the
+// installation at pp.InstallDir, so later deletes and overwrites can
+// check relay created them. Existing unmarked directories are refused.
+func (e FSExecutor) claim(pp plan.Paths, dirs []string) error {
+	m, err := paths.MarkerFor(pp.InstallDir, pp.Layout)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if e.DryRun {
+			if err := paths.CheckClaim(dir); err != nil {
+				return err
+			}
+			fmt.Println("[dry-run] mkdir:", dir)
+			continue
+		}
+
+		if err := paths.Claim(dir, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mkdirs creates dirs. Uses MkdirAll for idempotency.
+func (e FSExecutor) mkdirs(dirs []string) error {
+	for _, dir := range dirs {
+		if e.DryRun {
+			fmt.Println("[dry-run] mkdir:", dir)
+			continue
+		}
+
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create directory %s: %w", dir, err)
+		}
+	}
+	return nil
 }
 
 // downloadAndExtractJRE downloads and extracts the JRE archive.
@@ -95,6 +228,11 @@ func (e FSExecutor) downloadAndExtractJRE(ctx context.Context, dl downloader.HTT
 		return nil
 	}
 
+	// Extraction replaces the jre directory
+	if err := paths.CheckOwned(installDir); err != nil {
+		return err
+	}
+
 	// Download JRE archive
 	artifact := downloader.Artifact{
 		Name:   jre.Name,
@@ -106,6 +244,11 @@ func (e FSExecutor) downloadAndExtractJRE(ctx context.Context, dl downloader.HTT
 		return fmt.Errorf("download JRE: %w", err)
 	}
 
+	if err := downloader.VerifySHA256(jre.Target, jre.Checksum); err != nil {
+		os.Remove(jre.Target)
+		return fmt.Errorf("verify JRE: %w", err)
+	}
+
 	fmt.Println("Extracting JRE...")
 
 	// Extract to temp directory first (atomic extraction)
@@ -135,7 +278,7 @@ func (e FSExecutor) downloadAndExtractJRE(ctx context.Context, dl downloader.HTT
 	}
 
 	// Move to final location
-	jreDir := filepath.Join(installDir, "jre")
+	jreDir := runtime.BundledJREDir(installDir)
 	if err := os.RemoveAll(jreDir); err != nil {
 		os.RemoveAll(tmpDir)
 		return fmt.Errorf("remove existing jre: %w", err)
@@ -152,47 +295,115 @@ func (e FSExecutor) downloadAndExtractJRE(ctx context.Context, dl downloader.HTT
 	os.RemoveAll(tmpDir)
 	os.Remove(jre.Target) // Remove downloaded archive
 
+	if jre.Version != "" {
+		if err := runtime.WriteJREMarker(installDir, jre.Version); err != nil {
+			return fmt.Errorf("write JRE marker: %w", err)
+		}
+	}
+
 	fmt.Println("JRE installed to:", jreDir)
 	return nil
 }
 
-// execRemove deletes only owned paths.
-// Preserves ConfigDir to retain user configuration.
+// execRemove deletes only owned paths that carry relay's ownership marker.
+// Preserves ConfigDir to retain user configuration, and DataDir unless
+// the plan purges it.
 func (e FSExecutor) execRemove(p plan.RemovePlan) error {
-	for _, dir := range p.Paths.Owned() {
+	for _, dir := range p.Targets() {
+		if err := paths.CheckOwned(dir); err != nil {
+			return err
+		}
+	}
+
+	// Data kept inside a deleted directory must survive it
+	keep := ""
+	if !p.Purge {
+		keep = p.Paths.DataDir
+	}
+
+	for _, dir := range p.Targets() {
+		nested := keep != "" && keep != dir && paths.Within(keep, dir)
+
 		if e.DryRun {
-			fmt.Println("[dry-run] rm -rf:", dir)
+			if nested {
+				fmt.Printf("[dry-run] rm -rf: %s (keeping %s)\n", dir, keep)
+			} else {
+				fmt.Println("[dry-run] rm -rf:", dir)
+			}
 			continue
 		}
 
-		if err := os.RemoveAll(dir); err != nil {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		var err error
+		if nested {
+			err = removeExcept(dir, keep)
+		} else {
+			err = os.RemoveAll(dir)
+		}
+		if err != nil {
 			return fmt.Errorf("remove directory %s: %w", dir, err)
 		}
 	}
 
+	if p.Shortcut != nil {
+		if err := e.execShortcut(*p.Shortcut); err != nil {
+			return err
+		}
+	}
+
+	if p.Paths.Shared {
+		fmt.Println("Kept shared installation at", p.Paths.InstallDir, "(other users may need it)")
+	}
+
 	return nil
 }
 
-// execLaunch validates Java, generates the launcher, and runs it.
-func (e FSExecutor) execLaunch(ctx context.Context, p plan.LaunchPlan) error {
-	// Resolve Java path based on strategy
-	javaPath, err := runtime.ResolveJavaPath(p.Paths.InstallDir, string(p.JavaStrategy))
+// removeExcept deletes everything in dir except keep, which must be
+// inside it, and the directories leading to keep. Their ownership
+// markers stay too, so a later purge can delete them.
+func removeExcept(dir, keep string) error {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("resolve java: %w", err)
+		return err
 	}
 
-	gen, err := launcher.New(p, javaPath)
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case path == keep, entry.Name() == paths.OwnershipMarker:
+		case entry.IsDir() && paths.Within(keep, path):
+			if err := removeExcept(path, keep); err != nil {
+				return err
+			}
+		default:
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// execLaunch validates Java, generates the launcher, and runs it.
+func (e FSExecutor) execLaunch(ctx context.Context, p plan.LaunchPlan) error {
+	gen, p, err := e.prepareLauncher(p)
 	if err != nil {
-		return fmt.Errorf("create launcher: %w", err)
+		return err
 	}
 
 	if e.DryRun {
-		fmt.Printf("[dry-run] using java: %s\n", javaPath)
-		fmt.Println("[dry-run] generate launcher:", gen.Path())
 		fmt.Println("[dry-run] run launcher:", gen.Path())
 		return nil
 	}
 
+	// Burp's log and PID file go to the state directory
+	if err := e.claim(p.Paths, []string{p.Paths.StateDir}); err != nil {
+		return err
+	}
+
 	// Generate the launcher script
 	if err := gen.Generate(p); err != nil {
 		return fmt.Errorf("generate launcher: %w", err)
@@ -203,28 +414,169 @@ func (e FSExecutor) execLaunch(ctx context.Context, p plan.LaunchPlan) error {
 	return runner.Run(ctx, gen.Path())
 }
 
+// prepareLauncher resolves and validates Java and expands the heap,
+// returning the launcher generator and the plan with concrete JVM args.
+func (e FSExecutor) prepareLauncher(p plan.LaunchPlan) (launcher.Generator, plan.LaunchPlan, error) {
+	// Probes are cached, so unchanged Java installs aren't started again
+	prober := runtime.Prober{}
+	if !e.DryRun && paths.ClaimCache(p.Paths.CacheDir, p.Paths.InstallDir, p.Paths.Layout) == nil {
+		prober.CacheFile = runtime.ProbeCacheFile(p.Paths.CacheDir)
+	}
+
+	// Resolve Java path based on strategy
+	javaPath, err := runtime.ResolveJavaPath(runtime.JavaOptions{
+		Strategy:   string(p.JavaStrategy),
+		InstallDir: p.Paths.InstallDir,
+		JavaHome:   p.JavaHome,
+		Pin:        p.JavaPin,
+		MinVersion: p.JavaMin,
+		Prober:     prober,
+	})
+	if err != nil {
+		return nil, p, fmt.Errorf("resolve java: %w", err)
+	}
+
+	// Probe the selected binary up front - once the launcher backgrounds
+	// Burp, a broken or too-old JRE would otherwise fail silently
+	if _, err := runtime.VerifyJava(prober, javaPath, p.JavaMin); err != nil {
+		return nil, p, fmt.Errorf("validate java: %w\n%s", err, javaHint(p.JavaStrategy, p.JavaMin))
+	}
+
+	// Turn heap expressions (auto, 50%) into concrete -Xmx for this host
+	p.JVMArgs, err = expandHeap(p)
+	if err != nil {
+		return nil, p, err
+	}
+
+	gen, err := launcher.New(p, javaPath)
+	if err != nil {
+		return nil, p, fmt.Errorf("create launcher: %w", err)
+	}
+
+	if e.DryRun {
+		fmt.Printf("[dry-run] using java: %s\n", javaPath)
+		fmt.Printf("[dry-run] jvm args: %s\n", strings.Join(p.JVMArgs, " "))
+		fmt.Println("[dry-run] generate launcher:", gen.Path())
+	}
+	return gen, p, nil
+}
+
+// expandHeap resolves heap expressions in the launch plan against host memory.
+// Memory is only detected when an expression needs it; when it can't be,
+// the expressions are dropped and the JVM picks its default heap.
+func expandHeap(p plan.LaunchPlan) ([]string, error) {
+	needsMemory := runtime.IsHeapExpression(p.Heap)
+	for _, arg := range p.JVMArgs {
+		if v, ok := strings.CutPrefix(arg, "-Xmx"); ok && runtime.IsHeapExpression(v) {
+			needsMemory = true
+		}
+	}
+
+	var limit uint64
+	if needsMemory {
+		mem, err := sysinfo.Memory()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: detect memory for heap sizing: %v; using the JVM's default heap\n", err)
+			p.JVMArgs, p.Heap = runtime.DropHeapExpressions(p.JVMArgs, p.Heap)
+		}
+		limit = mem.Limit
+	}
+
+	args, err := runtime.ExpandJVMArgs(p.JVMArgs, p.Heap, limit)
+	if err != nil {
+		return nil, fmt.Errorf("expand heap: %w", err)
+	}
+	return args, nil
+}
+
+// javaHint suggests how to fix a Java validation failure for a strategy.
+func javaHint(strategy config.JavaStrategy, minVersion int) string {
+	switch strategy {
+	case config.JavaStrategyBundled, config.JavaStrategyManaged:
+		return "Reinstall the bundled JRE with 'relay jre update --force'."
+	case config.JavaStrategyPath:
+		return fmt.Sprintf("Point runtime.java.java_home at a Java %d+ installation.", minVersion)
+	default:
+		return fmt.Sprintf("Install Java %d+ or run 'relay java list' to see what relay found.", minVersion)
+	}
+}
+
 // execUpdate downloads the new version, replacing the existing JAR.
 func (e FSExecutor) execUpdate(ctx context.Context, p plan.UpdatePlan) error {
-	artifact := downloader.Artifact{
-		Name:   p.Artifact.Name,
-		URL:    p.Artifact.URL,
-		Target: p.Artifact.Target,
+	if err := paths.CheckOwned(p.Paths.InstallDir); err != nil {
+		return err
+	}
+	if p.Paths.Shared {
+		if err := checkSharedAccess(p.Paths.InstallDir, p.Group); err != nil {
+			return err
+		}
 	}
 
 	if e.DryRun {
 		fmt.Printf("[dry-run] update %s -> %s\n", p.CurrentVersion, p.TargetVersion)
-		fmt.Println("[dry-run] download:", artifact.Name)
-		fmt.Println("[dry-run]   url:", artifact.URL)
-		fmt.Println("[dry-run]   target:", artifact.Target)
-		return nil
+	} else {
+		fmt.Printf("Updating %s -> %s\n", p.CurrentVersion, p.TargetVersion)
 	}
 
-	fmt.Printf("Updating %s -> %s\n", p.CurrentVersion, p.TargetVersion)
-
 	dl := downloader.HTTPDownloader{
 		Timeout: 5 * time.Minute,
 		Retries: 3,
 	}
 
-	return dl.FetchWithProgress(ctx, artifact)
+	if err := e.fetchArtifact(ctx, dl, p.Artifact); err != nil || e.DryRun {
+		return err
+	}
+
+	if p.Paths.Shared {
+		if err := shareTree(p.Paths.InstallDir, p.Group); err != nil {
+			return fmt.Errorf("share install: %w", err)
+		}
+	}
+	return nil
+}
+
+// execRuntime installs, replaces or removes the bundled JRE.
+func (e FSExecutor) execRuntime(ctx context.Context, p plan.RuntimePlan) error {
+	// The bundled JRE lives in the install dir
+	if p.Paths.Shared {
+		if err := checkSharedAccess(p.Paths.InstallDir, ""); err != nil {
+			return err
+		}
+	}
+
+	switch p.Action {
+	case plan.RuntimeInstall:
+		if p.JREArtifact == nil {
+			return fmt.Errorf("runtime install plan has no JRE artifact")
+		}
+
+		if err := e.claim(p.Paths, []string{p.Paths.InstallDir}); err != nil {
+			return err
+		}
+
+		dl := downloader.HTTPDownloader{
+			Timeout: 5 * time.Minute,
+			Retries: 3,
+		}
+		return e.downloadAndExtractJRE(ctx, dl, p.JREArtifact, p.Paths.InstallDir)
+
+	case plan.RuntimeRemove:
+		if err := paths.CheckOwned(p.Paths.InstallDir); err != nil {
+			return err
+		}
+
+		jreDir := runtime.BundledJREDir(p.Paths.InstallDir)
+		if e.DryRun {
+			fmt.Println("[dry-run] rm -rf:", jreDir)
+			return nil
+		}
+
+		if err := os.RemoveAll(jreDir); err != nil {
+			return fmt.Errorf("remove directory %s: %w", jreDir, err)
+		}
+		return runtime.RemoveJREMarker(p.Paths.InstallDir)
+
+	default:
+		return fmt.Errorf("unsupported runtime action: %s", p.Action)
+	}
 }
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+func TestExecRemoveNestedData(t *testing.T) {
+	marker := paths.OwnershipMarker
+	tests := []struct {
+		name  string
+		data  string // DataDir, relative to the install directory
+		purge bool
+		files []string
+		kept  []string
+		gone  []string
+	}{
+		{
+			name:  "data inside install is kept",
+			data:  "data",
+			files: []string{marker, "burpsuite.jar", "data/" + marker, "data/project.burp"},
+			kept:  []string{marker, "data/" + marker, "data/project.burp"},
+			gone:  []string{"burpsuite.jar"},
+		},
+		{
+			name:  "directories leading to data keep their markers",
+			data:  "share/data",
+			files: []string{marker, "share/" + marker, "share/other/file", "share/data/" + marker, "share/data/project.burp"},
+			kept:  []string{marker, "share/" + marker, "share/data/" + marker, "share/data/project.burp"},
+			gone:  []string{"share/other"},
+		},
+		{
+			name:  "purge removes everything",
+			data:  "data",
+			purge: true,
+			files: []string{marker, "burpsuite.jar", "data/" + marker, "data/project.burp"},
+			gone:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			install := filepath.Join(root, "install")
+			for _, name := range tt.files {
+				path := filepath.Join(install, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			p := plan.RemovePlan{
+				Purge: tt.purge,
+				Paths: plan.Paths{
+					InstallDir: install,
+					DataDir:    filepath.Join(install, tt.data),
+					CacheDir:   filepath.Join(root, "cache"),
+					StateDir:   filepath.Join(root, "state"),
+				},
+			}
+			if err := (FSExecutor{}).Execute(context.Background(), p); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			for _, name := range tt.kept {
+				if _, err := os.Stat(filepath.Join(install, name)); err != nil {
+					t.Errorf("%s was removed: %v", name, err)
+				}
+			}
+			for _, name := range tt.gone {
+				if _, err := os.Stat(filepath.Join(install, name)); !os.IsNotExist(err) {
+					t.Errorf("%s was kept", name)
+				}
+			}
+		})
+	}
+}
+
+func TestExecRemoveRefusesUnmarked(t *testing.T) {
+	// A user's own directory that happens to hold a Burp JAR
+	install := t.TempDir()
+	jar := filepath.Join(install, "burpsuite.jar")
+	if err := os.WriteFile(jar, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plan.RemovePlan{Paths: plan.Paths{InstallDir: install}}
+	if err := (FSExecutor{}).Execute(context.Background(), p); err == nil {
+		t.Error("Execute() removed an unmarked directory")
+	}
+	if _, err := os.Stat(jar); err != nil {
+		t.Errorf("burpsuite.jar was removed: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/sdmrf/relay/internal/downloader"
+	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// execRepair applies the fixes in a repair plan. Fixes are independent,
+// so a failing one doesn't stop the rest; all failures are returned.
+// The JRE comes before the launcher, which points at it.
+func (e FSExecutor) execRepair(ctx context.Context, p plan.RepairPlan) error {
+	var errs []error
+
+	owned := p.Paths.Owned()
+	if p.Paths.Shared {
+		owned = append(owned, p.Paths.InstallDir)
+	}
+	for _, dir := range p.Dirs {
+		var err error
+		if slices.Contains(owned, dir) {
+			err = e.claim(p.Paths, []string{dir})
+		} else {
+			err = e.mkdirs([]string{dir})
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, dir := range p.Writable {
+		if err := e.makeWritable(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	dl := downloader.HTTPDownloader{
+		Timeout: 5 * time.Minute,
+		Retries: 3,
+	}
+
+	// Downloads change a shared install, which may not be ours to change
+	if p.Paths.Shared && (p.JREArtifact != nil || p.Artifact != nil) {
+		if err := checkSharedAccess(p.Paths.InstallDir, p.Group); err != nil {
+			errs = append(errs, err)
+			p.JREArtifact, p.Artifact = nil, nil
+		}
+	}
+
+	if p.JREArtifact != nil {
+		if err := e.downloadAndExtractJRE(ctx, dl, p.JREArtifact, p.Paths.InstallDir); err != nil {
+			errs = append(errs, fmt.Errorf("install JRE: %w", err))
+		}
+	}
+
+	if p.Artifact != nil {
+		if err := e.redownload(ctx, dl, p); err != nil {
+			errs = append(errs, fmt.Errorf("download %s: %w", p.Artifact.Name, err))
+		}
+	}
+
+	if p.Launch != nil {
+		if err := e.regenerateLauncher(*p.Launch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// makeWritable gives the owner of dir full access to it.
+func (e FSExecutor) makeWritable(dir string) error {
+	if e.DryRun {
+		fmt.Println("[dry-run] chmod u+rwx:", dir)
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("repair permissions: %w", err)
+	}
+	if err := os.Chmod(dir, info.Mode().Perm()|0o700); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("repair permissions: %s belongs to another user; change its owner, e.g. sudo chown -R $USER %s", dir, dir)
+		}
+		return fmt.Errorf("repair permissions: %w", err)
+	}
+	fmt.Println("Made writable:", dir)
+	return nil
+}
+
+// redownload replaces a missing or corrupt product download.
+func (e FSExecutor) redownload(ctx context.Context, dl downloader.HTTPDownloader, p plan.RepairPlan) error {
+	if !e.DryRun {
+		if err := paths.CheckOwned(p.Paths.InstallDir); err != nil {
+			return err
+		}
+	}
+
+	if err := e.fetchArtifact(ctx, dl, *p.Artifact); err != nil || e.DryRun {
+		return err
+	}
+
+	if p.Paths.Shared {
+		if err := shareTree(p.Paths.InstallDir, p.Group); err != nil {
+			return fmt.Errorf("share install: %w", err)
+		}
+	}
+	return nil
+}
+
+// regenerateLauncher writes the launcher for lp without running it. A dry
+// run doesn't resolve Java, which an earlier step of the same plan may
+// only be about to install.
+func (e FSExecutor) regenerateLauncher(lp plan.LaunchPlan) error {
+	if e.DryRun {
+		gen, err := launcher.New(lp, "")
+		if err != nil {
+			return fmt.Errorf("create launcher: %w", err)
+		}
+		fmt.Println("[dry-run] generate launcher:", gen.Path())
+		return nil
+	}
+
+	gen, lp, err := e.prepareLauncher(lp)
+	if err != nil {
+		return err
+	}
+
+	if err := gen.Generate(lp); err != nil {
+		return fmt.Errorf("generate launcher: %w", err)
+	}
+	fmt.Println("Regenerated launcher:", gen.Path())
+	return nil
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/sdmrf/relay/internal/paths"
+)
+
+// checkSharedAccess explains how to get write access to a shared install
+// directory the current user can't write.
+func checkSharedAccess(dir, group string) error {
+	if paths.Writable(dir) {
+		return nil
+	}
+
+	how := "run relay as root (sudo)"
+	if runtime.GOOS == "windows" {
+		how = "run relay from an elevated prompt"
+	}
+	if group != "" {
+		how += fmt.Sprintf(" or as a member of group %s", group)
+	}
+	return fmt.Errorf("shared install directory %s is not writable by this user: %s, or ask an administrator", dir, how)
+}
+
+// shareTree makes a shared install usable by every user. Everything
+// becomes readable by group and others; with a group set, the tree is
+// given to that group with write access, and directories get setgid so
+// files added by later updates keep the group. Windows installs inherit
+// the %ProgramData% ACLs instead.
+func shareTree(root, group string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	gid := -1
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			return fmt.Errorf("look up group %s: %w", group, err)
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return fmt.Errorf("group %s has non-numeric gid %s", group, g.Gid)
+		}
+	}
+
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if gid >= 0 {
+			if err := os.Lchown(path, -1, gid); err != nil {
+				return fmt.Errorf("set group of %s: %w", path, err)
+			}
+		}
+
+		mode := info.Mode().Perm() | 0o044
+		if info.IsDir() || mode&0o100 != 0 {
+			mode |= 0o011 // Search and execute follow the owner
+		}
+		if gid >= 0 {
+			mode |= 0o020
+			if info.IsDir() {
+				mode |= fs.ModeSetgid
+			}
+		}
+
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("set permissions of %s: %w", path, err)
+		}
+		return nil
+	})
+}
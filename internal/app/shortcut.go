@@ -0,0 +1,44 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// execShortcut creates or deletes the desktop shortcut for a launcher.
+func (e FSExecutor) execShortcut(p plan.ShortcutPlan) error {
+	sc, err := launcher.NewShortcut(launcher.ShortcutConfig{
+		Name:         p.Name,
+		Description:  "Start " + p.Name + " with relay",
+		LauncherPath: p.Launcher,
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.Remove {
+		if e.DryRun {
+			fmt.Println("[dry-run] remove shortcut:", sc.Path())
+			return nil
+		}
+		if err := sc.Remove(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove shortcut: %w", err)
+		}
+		fmt.Println("Removed shortcut:", sc.Path())
+		return nil
+	}
+
+	if e.DryRun {
+		fmt.Println("[dry-run] create shortcut:", sc.Path())
+		return nil
+	}
+	if err := sc.Create(); err != nil {
+		return fmt.Errorf("create shortcut: %w", err)
+	}
+	fmt.Println("Created shortcut:", sc.Path())
+	return nil
+}
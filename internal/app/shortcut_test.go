@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+func TestExecShortcut(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	sc := plan.ShortcutPlan{Name: "Burp Suite Professional (pro)", Launcher: "/opt/relay/pro/bin/burpsuite"}
+	if err := (FSExecutor{}).Execute(context.Background(), sc); err != nil {
+		t.Fatalf("Execute() create error = %v", err)
+	}
+
+	var created string
+	_ = filepath.WalkDir(home, func(path string, d os.DirEntry, err error) error {
+		if err == nil && strings.HasPrefix(d.Name(), sc.Name) {
+			created = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if created == "" {
+		t.Fatal("no shortcut was created")
+	}
+
+	// Removing the install removes its shortcut
+	install := t.TempDir()
+	rp := plan.RemovePlan{Paths: plan.Paths{InstallDir: filepath.Join(install, "missing")}, Shortcut: &plan.ShortcutPlan{Name: sc.Name, Remove: true}}
+	if err := (FSExecutor{}).Execute(context.Background(), rp); err != nil {
+		t.Fatalf("Execute() remove error = %v", err)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("shortcut %s still exists after remove", created)
+	}
+
+	// Removing a missing shortcut is not an error
+	sc.Remove = true
+	if err := (FSExecutor{}).Execute(context.Background(), sc); err != nil {
+		t.Errorf("Execute() removing a missing shortcut error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sdmrf/relay/internal/downloader"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// redacted replaces removed values in a support bundle.
+const redacted = "[REDACTED]"
+
+// secretPatterns find credentials in config, logs and scripts. Their
+// replacements keep the setting name so the reader can still see which
+// setting had a value.
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	// user:password@ in URLs
+	{regexp.MustCompile(`(?i)([a-z][a-z0-9+.-]*://)[^/\s:@]+:[^/\s@]+@`), "${1}" + redacted + "@"},
+	// password=..., api_key: ..., -Dhttp.proxyPassword=...
+	{regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api[_-]?key|license[_-]?key|credentials?)["']?\s*[=:]\s*)(?:"[^"]*"|'[^']*'|\S+)`), "${1}" + redacted},
+	// Authorization: Bearer ...
+	{regexp.MustCompile(`(?i)((?:bearer|basic)\s+)[a-z0-9._~+/=-]{8,}`), "${1}" + redacted},
+}
+
+// CompileRedactions compiles --redact patterns.
+func CompileRedactions(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// Redact removes secrets and the user's own patterns from data, and
+// replaces the home directory with ~.
+func Redact(data []byte, home string, patterns []*regexp.Regexp) []byte {
+	for _, p := range secretPatterns {
+		data = p.re.ReplaceAll(data, []byte(p.with))
+	}
+	for _, re := range patterns {
+		data = re.ReplaceAllLiteral(data, []byte(redacted))
+	}
+	if home != "" && home != string(filepath.Separator) {
+		// Not /home/al in /home/alice
+		re := regexp.MustCompile(regexp.QuoteMeta(home) + `([/\\\s"':]|$)`)
+		data = re.ReplaceAll(data, []byte("~${1}"))
+	}
+	return data
+}
+
+// execSupportBundle reads, trims and redacts the plan's files and writes
+// them to a zip archive.
+func (e FSExecutor) execSupportBundle(p plan.SupportBundlePlan) error {
+	patterns, err := CompileRedactions(p.Redact)
+	if err != nil {
+		return err
+	}
+
+	var sources []downloader.ArchiveSource
+	for _, f := range p.Files {
+		data := f.Data
+		if data == nil {
+			data, err = readTail(f.Path, f.Tail)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("read %s: %w", f.Name, err)
+			}
+		}
+		sources = append(sources, downloader.ArchiveSource{Name: f.Name, Data: Redact(data, p.Home, patterns)})
+	}
+
+	if e.DryRun {
+		fmt.Println("[dry-run] write:", p.Archive)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.Archive), 0o700); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+	if err := downloader.CreateZip(p.Archive, sources); err != nil {
+		return err
+	}
+
+	fmt.Println("Support bundle written to", p.Archive)
+	return nil
+}
+
+// readTail reads path, or only its last n bytes when n is positive.
+func readTail(path string, n int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	if n > 0 && info.Size() > n {
+		if _, err := f.Seek(-n, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// execSync executes the steps of a sync plan in order. A failed step
+// skips the rest of its install; other installs still sync, and every
+// failure is returned.
+func (e FSExecutor) execSync(ctx context.Context, p plan.SyncPlan) error {
+	var errs []error
+	failed := map[string]bool{}
+	for _, step := range p.Steps {
+		if failed[step.Install] {
+			continue
+		}
+
+		fmt.Printf("%s %s\n", step.Change, stepTitle(step))
+		if err := e.Execute(ctx, step.Plan); err != nil {
+			failed[step.Install] = true
+			errs = append(errs, fmt.Errorf("%s: %w", stepTitle(step), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func stepTitle(step plan.SyncStep) string {
+	if step.Install == "" {
+		return step.Summary
+	}
+	return step.Install + ": " + step.Summary
+}
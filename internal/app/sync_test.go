@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+func TestExecSyncSkipsRestOfFailedInstall(t *testing.T) {
+	dir := t.TempDir()
+	unowned := filepath.Join(dir, "unowned")
+	if err := os.Mkdir(unowned, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	skipped := filepath.Join(dir, "a-skipped")
+	created := filepath.Join(dir, "b-created")
+
+	p := plan.SyncPlan{Steps: []plan.SyncStep{
+		{Install: "a", Change: plan.SyncDelete, Summary: "remove", Plan: plan.RemovePlan{Paths: plan.Paths{InstallDir: unowned}}},
+		{Install: "a", Change: plan.SyncAdd, Summary: "dir", Plan: plan.RepairPlan{Dirs: []string{skipped}}},
+		{Install: "b", Change: plan.SyncAdd, Summary: "dir", Plan: plan.RepairPlan{Dirs: []string{created}}},
+	}}
+
+	err := FSExecutor{}.Execute(context.Background(), p)
+	if err == nil || !strings.Contains(err.Error(), "a: remove") {
+		t.Errorf("Execute() error = %v, want the failed step of install a", err)
+	}
+	if _, err := os.Stat(skipped); err == nil {
+		t.Error("later step of the failed install ran")
+	}
+	if _, err := os.Stat(created); err != nil {
+		t.Errorf("other install's step did not run: %v", err)
+	}
+}
+
+func TestExecSyncDryRunLauncherBeforeJRE(t *testing.T) {
+	// A fresh machine: the JRE the launcher needs is only planned
+	install := filepath.Join(t.TempDir(), "relay")
+	paths := plan.Paths{InstallDir: install, BinDir: filepath.Join(install, "bin")}
+
+	p := plan.SyncPlan{Steps: []plan.SyncStep{
+		{Change: plan.SyncAdd, Summary: "jre", Plan: plan.RuntimePlan{
+			Action:        plan.RuntimeInstall,
+			TargetVersion: "21.0.5+11",
+			Paths:         paths,
+			JREArtifact:   &plan.JREArtifact{Name: "jre", Version: "21.0.5+11", Target: filepath.Join(install, "jre.tar.gz"), ExtractTo: install},
+		}},
+		{Change: plan.SyncAdd, Summary: "launcher", Plan: plan.RepairPlan{
+			Paths:  paths,
+			Launch: &plan.LaunchPlan{Product: "burpsuite", Paths: paths, JavaMin: 17, JavaStrategy: config.JavaStrategyBundled},
+		}},
+	}}
+
+	if err := (FSExecutor{DryRun: true}).Execute(context.Background(), p); err != nil {
+		t.Errorf("Execute() dry run error = %v", err)
+	}
+	if _, err := os.Stat(install); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", install)
+	}
+}
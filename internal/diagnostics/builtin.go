@@ -0,0 +1,219 @@
+package diagnostics
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sdmrf/relay/internal/launcher"
+	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+// Space needed besides the product download, with headroom.
+const (
+	jreSpace   = 300 << 20 // Archive plus the extracted JRE
+	cacheSpace = 50 << 20
+)
+
+// Built-in checks. Products register their own checks and network
+// targets from their packages.
+func init() {
+	Register(Definition{
+		ID:       "java",
+		Name:     "Java",
+		Category: CategoryRuntime,
+		Run: func(ctx context.Context, env Env) []Check {
+			return []Check{CheckJava(env.Java, env.Config.Runtime.Java.MinVersion)}
+		},
+	})
+
+	Register(Definition{
+		ID:       "heap",
+		Name:     "Heap",
+		Category: CategoryRuntime,
+		Run: func(ctx context.Context, env Env) []Check {
+			return []Check{CheckHeap(env.Config.Runtime.Java.JVMArgs, env.Config.Runtime.Java.Heap)}
+		},
+	})
+
+	Register(Definition{
+		ID:        "jvm-args",
+		Name:      "JVM args",
+		Category:  CategoryRuntime,
+		DependsOn: []string{"java"},
+		Run: func(ctx context.Context, env Env) []Check {
+			java := env.Config.Runtime.Java
+			return CheckJVMArgs(ctx, env.Java, java.JVMArgs, java.Heap)
+		},
+	})
+
+	Register(Definition{
+		ID:       "display",
+		Name:     "Display",
+		Category: CategoryRuntime,
+		Run: func(ctx context.Context, env Env) []Check {
+			return []Check{CheckDisplay(env.Config.Runtime.Java.JVMArgs)}
+		},
+	})
+
+	Register(Definition{
+		ID:       "config",
+		Name:     "Config",
+		Category: CategoryConfig,
+		Run: func(ctx context.Context, env Env) []Check {
+			return []Check{CheckConfig(env.Origins, env.ConfigErr)}
+		},
+	})
+
+	Register(Definition{
+		ID:       "paths",
+		Name:     "Paths",
+		Category: CategoryPaths,
+		Run: func(ctx context.Context, env Env) []Check {
+			if env.PathsErr != nil {
+				return []Check{{Name: "Paths", Status: StatusFail, Message: env.PathsErr.Error()}}
+			}
+			return CheckPaths(env.Paths)
+		},
+	})
+
+	Register(Definition{
+		ID:        "legacy-paths",
+		Name:      "Legacy paths",
+		Category:  CategoryPaths,
+		DependsOn: []string{"paths"},
+		Run: func(ctx context.Context, env Env) []Check {
+			return CheckLegacyPaths(env.Paths, env.PathOptions)
+		},
+	})
+
+	Register(Definition{
+		ID:       "temp",
+		Name:     "Temp directory",
+		Category: CategoryPaths,
+		Run: func(ctx context.Context, env Env) []Check {
+			return []Check{CheckTempDir()}
+		},
+	})
+
+	Register(Definition{
+		ID:        "product",
+		Name:      "Product",
+		Category:  CategoryProduct,
+		DependsOn: []string{"paths"},
+		Run: func(ctx context.Context, env Env) []Check {
+			if env.ProductErr != nil {
+				return []Check{{Name: "Product", Status: StatusFail, Message: env.ProductErr.Error()}}
+			}
+			launch, err := env.Product.ResolveLaunch()
+			if err != nil {
+				return []Check{{Name: "Product", Status: StatusFail, Message: err.Error()}}
+			}
+			return []Check{CheckProduct(launch.Jar)}
+		},
+	})
+
+	Register(Definition{
+		ID:        "launcher",
+		Name:      "Launcher",
+		Category:  CategoryProduct,
+		DependsOn: []string{"paths"},
+		Run: func(ctx context.Context, env Env) []Check {
+			if env.Product == nil {
+				return nil
+			}
+			launch, err := env.Product.ResolveLaunch()
+			if err != nil {
+				return nil
+			}
+			// The launcher should start the Java relay selects now
+			javaPath, _ := runtime.ResolveJavaPath(env.Java)
+			gen, err := launcher.New(launch, javaPath)
+			if err != nil {
+				return nil
+			}
+			return []Check{CheckLauncher(gen.Path(), javaPath)}
+		},
+	})
+
+	Register(Definition{
+		ID:        "disk",
+		Name:      "Disk space",
+		Category:  CategoryPaths,
+		DependsOn: []string{"paths"},
+		Run: func(ctx context.Context, env Env) []Check {
+			return CheckDiskSpace(diskNeeds(env))
+		},
+	})
+
+	Register(Definition{
+		ID:       "network",
+		Name:     "Network",
+		Category: CategoryNetwork,
+		Timeout:  10 * time.Second,
+		Run: func(ctx context.Context, env Env) []Check {
+			if env.Offline {
+				return []Check{{Name: "Network", Status: StatusSkip, Message: "Skipped: offline mode"}}
+			}
+			return CheckNetwork(ctx, NetworkTargets(env))
+		},
+	})
+
+	// The bundled JRE is looked up in the release metadata, but only for
+	// strategies that may download it
+	RegisterNetworkTargets(func(env Env) []NetworkTarget {
+		switch config.JavaStrategy(env.Java.Strategy) {
+		case config.JavaStrategyAuto, config.JavaStrategyBundled, config.JavaStrategyManaged:
+		default:
+			return nil
+		}
+		metadata := env.Config.Runtime.Java.Bundled.Metadata
+		if metadata == "" {
+			metadata = runtime.DefaultJREMetadataURL
+		}
+		return []NetworkTarget{{Name: "JRE metadata", URL: metadata}}
+	})
+
+	// The configured product's download
+	RegisterNetworkTargets(func(env Env) []NetworkTarget {
+		if env.Product == nil {
+			return nil
+		}
+		install, err := env.Product.ResolveInstall()
+		if err != nil || install.Artifact.URL == "" {
+			return nil
+		}
+		return []NetworkTarget{{Name: env.Product.Name() + " download", URL: install.Artifact.URL}}
+	})
+}
+
+// diskNeeds returns the space installing or updating needs. Installing
+// fails without it; for an existing install only updates are at risk.
+func diskNeeds(env Env) []DiskNeed {
+	install := DiskNeed{
+		Name: "Install disk space",
+		Dir:  env.Paths.InstallDir,
+		Hint: "set paths.install to a larger volume",
+	}
+	if env.Product != nil {
+		if p, err := env.Product.ResolveInstall(); err == nil {
+			install.Size = p.Artifact.Space
+		}
+		if launch, err := env.Product.ResolveLaunch(); err == nil {
+			if _, err := os.Stat(launch.Jar); err != nil {
+				install.Required = true
+			}
+		}
+	}
+	if needsJRE, err := runtime.NeedsJRE(env.Java); err == nil && needsJRE {
+		install.Size += jreSpace
+	}
+
+	return []DiskNeed{install, {
+		Name: "Cache disk space",
+		Dir:  env.Paths.CacheDir,
+		Size: cacheSpace,
+		Hint: "remove files from that volume",
+	}}
+}
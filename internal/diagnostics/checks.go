@@ -1,24 +1,30 @@
 package diagnostics
 
 import (
+	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
 	"path/filepath"
-	"time"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/sdmrf/relay/internal/paths"
 	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/internal/sysinfo"
 	"github.com/sdmrf/relay/pkg/config"
 )
 
 // Check represents a single diagnostic check.
 type Check struct {
-	Name    string
-	Status  Status
-	Message string
-	Details string
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+	Fix     *Fix   `json:"fix,omitempty"` // Remediation relay doctor --fix can apply; nil if none
 }
 
 // Status represents the result status of a check.
@@ -28,52 +34,211 @@ const (
 	StatusOK Status = iota
 	StatusWarn
 	StatusFail
+	StatusSkip // Not run because a check it depends on failed
 )
 
-// CheckJava verifies Java installation and version.
-func CheckJava(minVersion int) Check {
+// MarshalText encodes the status as "ok", "warn", "fail" or "skip".
+func (s Status) MarshalText() ([]byte, error) {
+	switch s {
+	case StatusOK:
+		return []byte("ok"), nil
+	case StatusWarn:
+		return []byte("warn"), nil
+	case StatusFail:
+		return []byte("fail"), nil
+	case StatusSkip:
+		return []byte("skip"), nil
+	default:
+		return nil, fmt.Errorf("unknown status %d", int(s))
+	}
+}
+
+// CheckJava verifies the Java selected by the configured strategy
+// exists and meets the minimum version.
+func CheckJava(opts runtime.JavaOptions, minVersion int) Check {
 	check := Check{Name: "Java"}
 
-	info, err := runtime.GetJavaInfo()
+	// Downloading the bundled JRE only helps strategies that can use it
+	switch config.JavaStrategy(opts.Strategy) {
+	case config.JavaStrategyAuto, config.JavaStrategyBundled, config.JavaStrategyManaged:
+		check.Fix = &Fix{Action: FixJava}
+	}
+
+	path, err := runtime.ResolveJavaPath(opts)
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("No Java for strategy %s", opts.Strategy)
+		check.Details = err.Error()
+		return check
+	}
+
+	inst, err := runtime.ProbeJava(path)
 	if err != nil {
 		check.Status = StatusFail
-		check.Message = "Java not found in PATH"
+		check.Message = "Java failed to run"
+		check.Details = err.Error()
 		return check
 	}
 
-	if info.Version < minVersion {
+	if inst.Major < minVersion {
 		check.Status = StatusFail
-		check.Message = fmt.Sprintf("Java %d+ required, found %d", minVersion, info.Version)
-		check.Details = info.Path
+		check.Message = fmt.Sprintf("Java %d+ required, found %d", minVersion, inst.Major)
+		check.Details = inst.Path
+		return check
+	}
+
+	if inst.Arch != "" && !runtime.HostArch(inst.Arch) {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("Java %d is built for %s, not this host", inst.Major, inst.Arch)
+		check.Details = inst.Path
 		return check
 	}
 
 	check.Status = StatusOK
-	check.Message = fmt.Sprintf("Java %d found", info.Version)
-	check.Details = info.Path
+	check.Message = fmt.Sprintf("Java %d found (%s)", inst.Major, opts.Strategy)
+	check.Details = inst.Path
 
 	return check
 }
 
-// CheckConfig verifies the configuration file.
-func CheckConfig(cfgPath string) Check {
-	check := Check{Name: "Config"}
+// CheckHeap compares the configured maximum heap with host memory.
+func CheckHeap(jvmArgs []string, heap string) Check {
+	check := Check{Name: "Heap"}
+
+	mem, err := sysinfo.Memory()
+	if err != nil {
+		check.Status = StatusWarn
+		check.Message = "Unable to detect host memory"
+		check.Details = err.Error()
+		return check
+	}
 
-	cfg, err := config.Load(cfgPath)
+	xmx, err := runtime.MaxHeap(jvmArgs, heap, mem.Limit)
 	if err != nil {
 		check.Status = StatusFail
-		check.Message = fmt.Sprintf("Failed to load: %v", err)
+		check.Message = "Invalid heap setting"
+		check.Details = err.Error()
+		return check
+	}
+
+	if xmx == 0 {
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("JVM default heap (%s memory)", formatSize(mem.Limit))
 		return check
 	}
 
-	if err := cfg.Validate(); err != nil {
+	if xmx > mem.Limit {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("Heap %s exceeds memory limit %s", formatSize(xmx), formatSize(mem.Limit))
+		check.Details = "Lower runtime.java.heap or set it to auto or a percentage such as 50%"
+		return check
+	}
+
+	if mem.Available > 0 && xmx > mem.Available {
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("Heap %s exceeds available memory %s", formatSize(xmx), formatSize(mem.Available))
+		check.Details = "Close other applications or lower runtime.java.heap"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("Heap %s of %s memory", formatSize(xmx), formatSize(mem.Limit))
+
+	return check
+}
+
+// CheckJVMArgs runs the resolved Java with the configured JVM arguments
+// and reports each error or warning it prints as a separate check.
+func CheckJVMArgs(ctx context.Context, opts runtime.JavaOptions, jvmArgs []string, heap string) []Check {
+	path, err := runtime.ResolveJavaPath(opts)
+	if err != nil {
+		return []Check{{Name: "JVM args", Status: StatusSkip, Message: "No Java to check JVM args with"}}
+	}
+
+	// Heap expressions such as -Xmx50% are relay's, not the JVM's
+	var limit uint64
+	if mem, err := sysinfo.Memory(); err == nil {
+		limit = mem.Limit
+	}
+	args, err := runtime.ExpandJVMArgs(jvmArgs, heap, limit)
+	if err != nil {
+		args = nil
+		for _, arg := range jvmArgs {
+			if !strings.HasPrefix(arg, "-Xmx") {
+				args = append(args, arg)
+			}
+		}
+	}
+
+	problems, err := runtime.CheckJVMArgs(ctx, path, args)
+	if err != nil {
+		return []Check{{Name: "JVM args", Status: StatusWarn, Message: "Unable to check JVM args", Details: err.Error()}}
+	}
+
+	if len(problems) == 0 {
+		check := Check{Name: "JVM args", Status: StatusOK, Message: "JVM args accepted"}
+		if inst, err := runtime.ProbeJava(path); err == nil {
+			check.Message = fmt.Sprintf("JVM args accepted by Java %d", inst.Major)
+		}
+		return []Check{check}
+	}
+
+	var checks []Check
+	for _, p := range problems {
+		check := Check{Name: "JVM args", Message: p.Message}
+		if p.Arg != "" {
+			check.Message = fmt.Sprintf("%s: %s", p.Arg, p.Message)
+		}
+		if p.Fatal {
+			check.Status = StatusFail
+			check.Details = "Fix or remove it in runtime.java.jvm_args; the product won't start"
+		} else {
+			check.Status = StatusWarn
+			check.Details = "Remove it from runtime.java.jvm_args"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// formatSize formats bytes as a human-readable size.
+func formatSize(b uint64) string {
+	const gib = 1 << 30
+	if b >= gib {
+		return fmt.Sprintf("%.1f GB", float64(b)/gib)
+	}
+	return fmt.Sprintf("%d MB", b>>20)
+}
+
+// CheckConfig reports the result of loading the layered configuration.
+func CheckConfig(origins config.Origins, loadErr error) Check {
+	check := Check{Name: "Config"}
+
+	if loadErr != nil {
 		check.Status = StatusFail
-		check.Message = fmt.Sprintf("Validation failed: %v", err)
+		check.Message = loadErr.Error()
 		return check
 	}
 
+	// List the files and variables that contributed settings
+	seen := map[string]bool{}
+	var sources []string
+	for _, key := range config.Keys() {
+		o := origins[key]
+		if o.Source == config.SourceDefault || seen[o.String()] {
+			continue
+		}
+		seen[o.String()] = true
+		sources = append(sources, o.String())
+	}
+
 	check.Status = StatusOK
-	check.Message = fmt.Sprintf("Loaded from %s", cfgPath)
+	if len(sources) == 0 {
+		check.Message = "Using defaults"
+		return check
+	}
+	check.Message = fmt.Sprintf("Loaded %d layer(s) over defaults", len(sources))
+	check.Details = strings.Join(sources, "\n    ")
 
 	return check
 }
@@ -82,8 +247,12 @@ func CheckConfig(cfgPath string) Check {
 func CheckPaths(p paths.Paths) []Check {
 	checks := []Check{}
 
-	// Check install directory
-	checks = append(checks, checkDirectory("Install directory", p.InstallDir))
+	// Check install directory; a shared one is usually read-only
+	if p.Shared {
+		checks = append(checks, checkSharedDirectory(p.InstallDir))
+	} else {
+		checks = append(checks, checkDirectory("Install directory", p.InstallDir))
+	}
 
 	// Check data directory
 	checks = append(checks, checkDirectory("Data directory", p.DataDir))
@@ -94,19 +263,43 @@ func CheckPaths(p paths.Paths) []Check {
 	// Check cache directory
 	checks = append(checks, checkDirectory("Cache directory", p.CacheDir))
 
+	// Check state directory
+	checks = append(checks, checkDirectory("State directory", p.StateDir))
+
 	return checks
 }
 
-// CheckProduct verifies the product installation.
-func CheckProduct(installDir string) Check {
+// CheckLegacyPaths warns about relay directories left where an older
+// release put them. It returns no checks when there are none.
+func CheckLegacyPaths(p paths.Paths, opts paths.Options) []Check {
+	found, err := paths.FindLegacy(p, opts)
+	if err != nil || len(found) == 0 {
+		return nil
+	}
+
+	var details []string
+	for _, r := range found {
+		details = append(details, fmt.Sprintf("%s -> %s", r.From, r.To))
+	}
+	return []Check{{
+		Name:    "Legacy paths",
+		Status:  StatusWarn,
+		Message: fmt.Sprintf("%d directory(s) at locations this version no longer uses; run 'relay install' to move them", len(found)),
+		Details: strings.Join(details, "\n    "),
+	}}
+}
+
+// CheckProduct verifies the product JAR the launcher runs.
+func CheckProduct(jarPath string) Check {
 	check := Check{Name: "Product"}
+	name := filepath.Base(jarPath)
 
-	jarPath := filepath.Join(installDir, "burpsuite.jar")
 	info, err := os.Stat(jarPath)
 	if os.IsNotExist(err) {
 		check.Status = StatusWarn
-		check.Message = "Burp Suite JAR not found"
+		check.Message = name + " not found"
 		check.Details = jarPath
+		check.Fix = &Fix{Action: FixProduct}
 		return check
 	}
 	if err != nil {
@@ -115,40 +308,227 @@ func CheckProduct(installDir string) Check {
 		return check
 	}
 
+	// An interrupted download leaves a JAR without a zip directory
+	zr, err := zip.OpenReader(jarPath)
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = name + " is corrupt"
+		check.Details = fmt.Sprintf("%s: %v", jarPath, err)
+		check.Fix = &Fix{Action: FixProduct}
+		return check
+	}
+	zr.Close()
+
 	check.Status = StatusOK
-	check.Message = fmt.Sprintf("Burp Suite JAR present (%d MB)", info.Size()/1024/1024)
+	check.Message = fmt.Sprintf("%s present (%d MB)", name, info.Size()/1024/1024)
 	check.Details = jarPath
 
 	return check
 }
 
-// CheckNetwork verifies network connectivity to PortSwigger.
-func CheckNetwork() Check {
-	check := Check{Name: "Network"}
+// CheckLauncher verifies a generated launcher still starts the Java that
+// relay would select now. A missing launcher is fine: relay launch
+// writes it.
+func CheckLauncher(launcherPath, javaPath string) Check {
+	check := Check{Name: "Launcher", Details: launcherPath}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://portswigger.net", nil)
+	info, err := os.Stat(launcherPath)
+	if os.IsNotExist(err) {
+		check.Status = StatusOK
+		check.Message = "Not generated yet; 'relay launch' creates it"
+		return check
+	}
 	if err != nil {
 		check.Status = StatusFail
-		check.Message = fmt.Sprintf("Failed to create request: %v", err)
+		check.Message = fmt.Sprintf("Error: %v", err)
 		return check
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	check.Fix = &Fix{Action: FixLauncher}
+
+	if info.Mode().Perm()&0o100 == 0 && paths.CurrentOS() != paths.Windows {
+		check.Status = StatusWarn
+		check.Message = "Launcher is not executable"
+		return check
+	}
+
+	content, err := os.ReadFile(launcherPath)
 	if err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("Error: %v", err)
+		return check
+	}
+	if javaPath != "" && !strings.Contains(string(content), javaPath) {
 		check.Status = StatusWarn
-		check.Message = "portswigger.net unreachable"
-		check.Details = err.Error()
+		check.Message = "Launcher uses a different Java than relay selects"
+		check.Details = fmt.Sprintf("%s (want %s)", launcherPath, javaPath)
 		return check
 	}
-	defer resp.Body.Close()
 
 	check.Status = StatusOK
-	check.Message = "portswigger.net reachable"
+	check.Message = "Launcher up to date"
+	return check
+}
+
+// DiskNeed is the free space a directory needs for relay to work in it.
+type DiskNeed struct {
+	Name     string // Check name, e.g. "Install disk space"
+	Dir      string
+	Size     uint64
+	Required bool // Missing space fails rather than warns, e.g. before install
+	Hint     string
+}
+
+// CheckDiskSpace compares the free space on the filesystem of each
+// directory with what it needs.
+func CheckDiskSpace(needs []DiskNeed) []Check {
+	var checks []Check
+	for _, n := range needs {
+		check := Check{Name: n.Name, Details: n.Dir}
+
+		free, err := sysinfo.DiskFree(n.Dir)
+		switch {
+		case err != nil:
+			check.Status = StatusWarn
+			check.Message = "Unable to detect free space"
+			check.Details = err.Error()
+		case free < n.Size:
+			check.Status = StatusWarn
+			if n.Required {
+				check.Status = StatusFail
+			}
+			check.Message = fmt.Sprintf("%s free, %s needed", formatSize(free), formatSize(n.Size))
+			check.Details = fmt.Sprintf("%s\n    Free up %s or %s", n.Dir, formatSize(n.Size-free), n.Hint)
+		default:
+			check.Status = StatusOK
+			check.Message = fmt.Sprintf("%s free", formatSize(free))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// CheckDisplay verifies Burp's window can open. Headless JVM args mean no
+// display is needed.
+func CheckDisplay(jvmArgs []string) Check {
+	check := Check{Name: "Display"}
+
+	for _, arg := range jvmArgs {
+		if arg == "-Djava.awt.headless=true" {
+			check.Status = StatusOK
+			check.Message = "Headless mode configured"
+			return check
+		}
+	}
+
+	ok, how := sysinfo.Display()
+	if ok {
+		check.Status = StatusOK
+		check.Message = "Display available"
+		check.Details = how
+		return check
+	}
+
+	check.Status = StatusWarn
+	check.Message = fmt.Sprintf("No display (%s); Burp's window cannot open", how)
+	check.Details = "Connect with X forwarding (ssh -X), or run Burp Suite Professional headless by\n" +
+		"    adding -Djava.awt.headless=true to runtime.java.jvm_args"
+	return check
+}
 
+// CheckTempDir verifies the temporary directory, where Burp and relay
+// write scratch files, is writable.
+func CheckTempDir() Check {
+	dir := os.TempDir()
+	check := Check{Name: "Temp directory", Details: dir}
+
+	f, err := os.CreateTemp(dir, ".relay-test-")
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = "Temp directory not writable"
+		hint := "TMPDIR"
+		if paths.CurrentOS() == paths.Windows {
+			hint = "TEMP"
+		}
+		check.Details = fmt.Sprintf("%v\n    Set %s to a writable directory", err, hint)
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	check.Status = StatusOK
+	check.Message = "Temp directory writable"
+	return check
+}
+
+// CheckProxyListener verifies Burp's proxy can listen on addr. A port held
+// by burpPID, the Burp Suite relay started, is expected.
+func CheckProxyListener(addr string, burpPID int) Check {
+	check := Check{Name: "Proxy listener"}
+
+	ln, err := net.Listen("tcp", addr)
+	if err == nil {
+		ln.Close()
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("%s available", addr)
+		return check
+	}
+
+	_, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+	owner, ownerErr := sysinfo.PortOwner(port)
+
+	switch {
+	case ownerErr == nil && owner.PID == burpPID:
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("%s in use by the running Burp Suite (PID %d)", addr, owner.PID)
+	case ownerErr == nil:
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("%s in use by %s (PID %d)", addr, orUnknown(owner.Name), owner.PID)
+		check.Details = "Stop that process, or move Burp's listener in Settings > Tools > Proxy\n" +
+			"    and set burp.proxy_listeners to match"
+	case errors.Is(err, syscall.EADDRINUSE):
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("%s in use by another process", addr)
+		check.Details = fmt.Sprintf("%v\n    Find it with 'sudo lsof -iTCP:%d -sTCP:LISTEN' or 'ss -ltnp'", ownerErr, port)
+	default:
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("Cannot listen on %s", addr)
+		check.Details = err.Error()
+	}
+	return check
+}
+
+func orUnknown(name string) string {
+	if name == "" {
+		return "unknown process"
+	}
+	return name
+}
+
+// checkSharedDirectory verifies a machine-wide install directory exists.
+// Only admins are expected to be able to write it.
+func checkSharedDirectory(path string) Check {
+	check := Check{Name: "Install directory", Details: path}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		check.Status = StatusWarn
+		check.Message = "Shared install missing; an administrator must run 'relay install'"
+	case err != nil:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("Error: %v", err)
+	case !info.IsDir():
+		check.Status = StatusFail
+		check.Message = "Path is not a directory"
+	case paths.Writable(path):
+		check.Status = StatusOK
+		check.Message = "Shared install, writable by you"
+	default:
+		check.Status = StatusOK
+		check.Message = "Shared install, read-only"
+	}
 	return check
 }
 
@@ -160,6 +540,7 @@ func checkDirectory(name, path string) Check {
 		check.Status = StatusWarn
 		check.Message = "Directory does not exist"
 		check.Details = path
+		check.Fix = &Fix{Action: FixCreateDir, Path: path}
 		return check
 	}
 	if err != nil {
@@ -182,6 +563,7 @@ func checkDirectory(name, path string) Check {
 		check.Status = StatusWarn
 		check.Message = "Directory not writable"
 		check.Details = path
+		check.Fix = &Fix{Action: FixPermissions, Path: path}
 		return check
 	}
 	f.Close()
@@ -0,0 +1,127 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/paths"
+)
+
+func TestCheckProductFix(t *testing.T) {
+	tests := []struct {
+		name       string
+		jar        func(path string) error
+		wantStatus Status
+		wantFix    bool
+	}{
+		{
+			name:       "missing",
+			jar:        func(string) error { return nil },
+			wantStatus: StatusWarn,
+			wantFix:    true,
+		},
+		{
+			name:       "corrupt",
+			jar:        func(path string) error { return os.WriteFile(path, []byte("partial download"), 0o644) },
+			wantStatus: StatusFail,
+			wantFix:    true,
+		},
+		{
+			name: "valid",
+			jar: func(path string) error {
+				f, err := os.Create(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				zw := zip.NewWriter(f)
+				if _, err := zw.Create("META-INF/MANIFEST.MF"); err != nil {
+					return err
+				}
+				return zw.Close()
+			},
+			wantStatus: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := tt.jar(filepath.Join(dir, "burpsuite.jar")); err != nil {
+				t.Fatal(err)
+			}
+
+			check := CheckProduct(filepath.Join(dir, "burpsuite.jar"))
+			if check.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v (%s)", check.Status, tt.wantStatus, check.Message)
+			}
+			if gotFix := check.Fix != nil && check.Fix.Action == FixProduct; gotFix != tt.wantFix {
+				t.Errorf("Fix = %v, want product fix %v", check.Fix, tt.wantFix)
+			}
+		})
+	}
+}
+
+func TestReportFixes(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	report := NewReport()
+	report.Add(checkDirectory("Cache directory", missing))
+	report.Add(checkDirectory("State directory", missing))
+	report.Add(Check{Name: "Java", Status: StatusOK, Fix: &Fix{Action: FixJava}})
+
+	fixes := report.Fixes()
+	if len(fixes) != 1 {
+		t.Fatalf("Fixes() = %v, want one create fix", fixes)
+	}
+	if want := (Fix{Action: FixCreateDir, Path: missing}); fixes[0] != want {
+		t.Errorf("Fixes()[0] = %v, want %v", fixes[0], want)
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		need DiskNeed
+		want Status
+	}{
+		{"enough", DiskNeed{Name: "Disk", Dir: dir, Size: 1}, StatusOK},
+		{"short for update", DiskNeed{Name: "Disk", Dir: dir, Size: 1 << 62}, StatusWarn},
+		{"short for install", DiskNeed{Name: "Disk", Dir: dir, Size: 1 << 62, Required: true}, StatusFail},
+		{"missing dir measured at parent", DiskNeed{Name: "Disk", Dir: filepath.Join(dir, "a", "b"), Size: 1}, StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := CheckDiskSpace([]DiskNeed{tt.need})
+			if len(checks) != 1 || checks[0].Status != tt.want {
+				t.Errorf("CheckDiskSpace() = %+v, want status %v", checks, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDisplay(t *testing.T) {
+	if CheckDisplay([]string{"-Djava.awt.headless=true"}).Status != StatusOK {
+		t.Error("CheckDisplay() with headless mode should pass")
+	}
+
+	if paths.CurrentOS() != paths.Linux {
+		t.Skip("display variables are Linux-specific")
+	}
+
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	if got := CheckDisplay(nil); got.Status != StatusWarn {
+		t.Errorf("CheckDisplay() without display = %v, want warning", got.Status)
+	}
+
+	t.Setenv("DISPLAY", ":0")
+	if got := CheckDisplay(nil); got.Status != StatusOK {
+		t.Errorf("CheckDisplay() with DISPLAY = %v, want OK", got.Status)
+	}
+}
@@ -0,0 +1,38 @@
+package diagnostics
+
+import "fmt"
+
+// FixAction is a kind of remediation relay doctor --fix can apply.
+type FixAction string
+
+const (
+	FixCreateDir   FixAction = "create-dir"  // Create a missing directory
+	FixPermissions FixAction = "permissions" // Make a directory writable by its owner
+	FixProduct     FixAction = "product"     // Download the product JAR again
+	FixJava        FixAction = "java"        // Download the bundled JRE
+	FixLauncher    FixAction = "launcher"    // Regenerate the launcher script
+)
+
+// Fix is a remediation for a failing check. Checks only describe the fix;
+// relay doctor turns the fixes it applies into a plan.RepairPlan.
+type Fix struct {
+	Action FixAction `json:"action"`
+	Path   string    `json:"path,omitempty"` // Directory for FixCreateDir and FixPermissions
+}
+
+func (f Fix) String() string {
+	switch f.Action {
+	case FixCreateDir:
+		return "create " + f.Path
+	case FixPermissions:
+		return fmt.Sprintf("make %s writable", f.Path)
+	case FixProduct:
+		return "download the product JAR again"
+	case FixJava:
+		return "download the bundled JRE"
+	case FixLauncher:
+		return "regenerate the launcher"
+	default:
+		return string(f.Action)
+	}
+}
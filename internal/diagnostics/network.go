@@ -0,0 +1,205 @@
+package diagnostics
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// NetworkTarget is a host relay downloads from.
+type NetworkTarget struct {
+	Name string // Shown in the report, e.g. "burpsuite download"
+	URL  string // The URL relay requests
+}
+
+var (
+	targetsMu sync.Mutex
+	targetFns []func(Env) []NetworkTarget
+)
+
+// RegisterNetworkTargets adds a source of hosts for the network check.
+// Products register the hosts their artifacts come from.
+func RegisterNetworkTargets(fn func(Env) []NetworkTarget) {
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+	targetFns = append(targetFns, fn)
+}
+
+// NetworkTargets returns the hosts relay would contact with env's config,
+// without duplicate URLs.
+func NetworkTargets(env Env) []NetworkTarget {
+	targetsMu.Lock()
+	fns := append([]func(Env) []NetworkTarget{}, targetFns...)
+	targetsMu.Unlock()
+
+	seen := map[string]bool{}
+	var targets []NetworkTarget
+	for _, fn := range fns {
+		for _, t := range fn(env) {
+			if !seen[t.URL] {
+				seen[t.URL] = true
+				targets = append(targets, t)
+			}
+		}
+	}
+	return targets
+}
+
+// CheckNetwork checks every target in parallel and returns their results
+// in order.
+func CheckNetwork(ctx context.Context, targets []NetworkTarget) []Check {
+	if len(targets) == 0 {
+		return []Check{{Name: "Network", Status: StatusOK, Message: "No download sources configured"}}
+	}
+
+	results := make([][]Check, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t NetworkTarget) {
+			defer wg.Done()
+			results[i] = CheckEndpoint(ctx, t, nil)
+		}(i, t)
+	}
+	wg.Wait()
+
+	var checks []Check
+	for _, r := range results {
+		checks = append(checks, r...)
+	}
+	return checks
+}
+
+// CheckEndpoint checks a download source one step at a time: DNS lookup,
+// TCP connect, TLS handshake and HEAD request, reporting each with its
+// latency. It stops at the first step that fails. Through a proxy from
+// the environment, DNS and TCP are checked against the proxy and the
+// handshake is part of the request. tlsConfig may be nil.
+func CheckEndpoint(ctx context.Context, target NetworkTarget, tlsConfig *tls.Config) []Check {
+	step := func(status Status, format string, args ...any) Check {
+		return Check{Name: target.Name, Status: status, Message: fmt.Sprintf(format, args...)}
+	}
+	fail := func(c Check, err error) []Check {
+		c.Details = err.Error()
+		return []Check{c}
+	}
+
+	u, err := url.Parse(target.URL)
+	if err != nil || u.Hostname() == "" {
+		return []Check{{Name: target.Name, Status: StatusFail, Message: "Invalid URL " + target.URL}}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.URL, nil)
+	if err != nil {
+		return fail(step(StatusFail, "Invalid URL %s", target.URL), err)
+	}
+	req.Header.Set("User-Agent", "relay")
+
+	proxy, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return fail(step(StatusWarn, "Invalid proxy setting"), err)
+	}
+	host, port := u.Hostname(), portOf(u)
+	via := ""
+	if proxy != nil {
+		host, port = proxy.Hostname(), portOf(proxy)
+		via = " (proxy)"
+	}
+
+	var checks []Check
+
+	start := time.Now()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return append(checks, fail(step(StatusWarn, "DNS: cannot resolve %s%s", host, via), err)...)
+	}
+	checks = append(checks, step(StatusOK, "DNS: %s%s resolved in %s", host, via, since(start)))
+
+	start = time.Now()
+	var dialer net.Dialer
+	var conn net.Conn
+	for _, addr := range addrs {
+		if conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(addr.IP.String(), port)); err == nil {
+			break
+		}
+	}
+	if conn == nil {
+		return append(checks, fail(step(StatusWarn, "TCP: cannot connect to %s:%s%s", host, port, via), err)...)
+	}
+	defer conn.Close()
+	checks = append(checks, step(StatusOK, "TCP: connected to %s in %s", conn.RemoteAddr(), since(start)))
+
+	var resp *http.Response
+	start = time.Now()
+	if proxy != nil {
+		client := &http.Client{
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+			// A redirect shows the source answered; don't follow it
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+		resp, err = client.Do(req)
+	} else {
+		if u.Scheme == "https" {
+			cfg := &tls.Config{ServerName: u.Hostname()}
+			if tlsConfig != nil {
+				cfg = tlsConfig.Clone()
+				cfg.ServerName = u.Hostname()
+			}
+			tlsConn := tls.Client(conn, cfg)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				return append(checks, fail(step(StatusWarn, "TLS: handshake with %s failed", u.Hostname()), err)...)
+			}
+			checks = append(checks, step(StatusOK, "TLS: %s handshake in %s", tls.VersionName(tlsConn.ConnectionState().Version), since(start)))
+			conn = tlsConn
+			start = time.Now()
+		}
+		resp, err = roundTrip(ctx, conn, req)
+	}
+	if err != nil {
+		return append(checks, fail(step(StatusWarn, "HTTP: HEAD %s failed", target.URL), err)...)
+	}
+	resp.Body.Close()
+
+	status := StatusOK
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusMethodNotAllowed {
+		status = StatusWarn
+	}
+	check := step(status, "HTTP: %s in %s", resp.Status, since(start))
+	if status != StatusOK {
+		check.Details = target.URL
+	}
+	return append(checks, check)
+}
+
+// roundTrip sends req over conn and reads the response headers.
+func roundTrip(ctx context.Context, conn net.Conn, req *http.Request) (*http.Response, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	req.Close = true
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	return http.ReadResponse(bufio.NewReader(conn), req)
+}
+
+// portOf returns u's port, or the default for its scheme.
+func portOf(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "http" {
+		return "80"
+	}
+	return "443"
+}
+
+func since(start time.Time) string {
+	return time.Since(start).Round(time.Millisecond).String()
+}
@@ -0,0 +1,65 @@
+package diagnostics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckEndpoint(t *testing.T) {
+	ok := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+	}))
+	defer ok.Close()
+	tlsConfig := ok.Client().Transport.(*http.Transport).TLSClientConfig
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	// A port nothing listens on
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want []Status
+		last string // Prefix of the last check's message
+	}{
+		{name: "https", url: ok.URL, want: []Status{StatusOK, StatusOK, StatusOK, StatusOK}, last: "HTTP: 200 OK"},
+		{name: "not found", url: missing.URL + "/jar", want: []Status{StatusOK, StatusOK, StatusWarn}, last: "HTTP: 404 Not Found"},
+		{name: "connection refused", url: closed, want: []Status{StatusOK, StatusWarn}, last: "TCP: cannot connect"},
+		{name: "invalid url", url: "://", want: []Status{StatusFail}, last: "Invalid URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := CheckEndpoint(context.Background(), NetworkTarget{Name: "Test", URL: tt.url}, tlsConfig)
+
+			var got []Status
+			for _, c := range checks {
+				got = append(got, c.Status)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("statuses = %v, want %v (%+v)", got, tt.want, checks)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("statuses = %v, want %v (%+v)", got, tt.want, checks)
+				}
+			}
+			if last := checks[len(checks)-1].Message; !strings.HasPrefix(last, tt.last) {
+				t.Errorf("last message = %q, want prefix %q", last, tt.last)
+			}
+		})
+	}
+}
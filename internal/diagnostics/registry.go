@@ -0,0 +1,266 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/sdmrf/relay/internal/runtime"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+// DefaultTimeout bounds checks that don't declare a timeout.
+const DefaultTimeout = 30 * time.Second
+
+// Check categories, in report order.
+const (
+	CategoryRuntime = "runtime"
+	CategoryConfig  = "config"
+	CategoryPaths   = "paths"
+	CategoryProduct = "product"
+	CategoryNetwork = "network"
+)
+
+var categoryOrder = []string{CategoryRuntime, CategoryConfig, CategoryPaths, CategoryProduct, CategoryNetwork}
+
+// Env is what checks inspect: the loaded config and resolved paths,
+// including the errors from loading and resolving them.
+type Env struct {
+	Config      config.Config
+	Origins     config.Origins
+	ConfigErr   error
+	Paths       paths.Paths
+	PathsErr    error
+	PathOptions paths.Options
+	Java        runtime.JavaOptions
+	Product     product.Product // nil when ProductErr is set
+	ProductErr  error
+	Offline     bool // --offline: checks must not use the network
+}
+
+// Definition describes a registered check.
+type Definition struct {
+	ID        string        // Name used by --only and --skip, e.g. "java"
+	Name      string        // Shown when the check is skipped or times out
+	Category  string        // One of the Category constants; orders the report
+	DependsOn []string      // Checks that must pass first; skipped if one fails
+	Timeout   time.Duration // Zero means DefaultTimeout
+	Run       func(ctx context.Context, env Env) []Check
+}
+
+// Registry holds check definitions and runs them.
+type Registry struct {
+	mu   sync.Mutex
+	defs []Definition
+}
+
+// Default is the registry relay doctor runs. Built-in checks register
+// themselves; products add theirs from their package init.
+var Default = &Registry{}
+
+// Register adds a check to the default registry.
+func Register(d Definition) {
+	Default.Register(d)
+}
+
+// Register adds a check. It panics on a duplicate ID, which is a
+// programming error.
+func (r *Registry) Register(d Definition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.defs {
+		if existing.ID == d.ID {
+			panic(fmt.Sprintf("diagnostics: check %q registered twice", d.ID))
+		}
+	}
+	r.defs = append(r.defs, d)
+}
+
+// IDs returns the registered check IDs in report order.
+func (r *Registry) IDs() []string {
+	var ids []string
+	for _, d := range r.sorted() {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+// Selection picks which checks run by ID. Empty Only means every check;
+// dependencies of selected checks run too unless skipped.
+type Selection struct {
+	Only []string
+	Skip []string
+}
+
+// Run runs the selected checks concurrently, each once its dependencies
+// have finished, and returns their results in report order. A check that
+// outlives its timeout or ctx is reported as timed out.
+func (r *Registry) Run(ctx context.Context, env Env, sel Selection) (*Report, error) {
+	defs, err := r.selected(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]Check, len(defs))
+	done := map[string]chan struct{}{}
+	failed := map[string]bool{}
+	var mu sync.Mutex
+	for _, d := range defs {
+		done[d.ID] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i, d := range defs {
+		wg.Add(1)
+		go func(i int, d Definition) {
+			defer wg.Done()
+			defer close(done[d.ID])
+
+			var blocked []string
+			for _, dep := range d.DependsOn {
+				ch, ok := done[dep]
+				if !ok {
+					continue // Skipped with --skip
+				}
+				<-ch
+				mu.Lock()
+				if failed[dep] {
+					blocked = append(blocked, dep)
+				}
+				mu.Unlock()
+			}
+
+			var checks []Check
+			if len(blocked) > 0 {
+				checks = []Check{{
+					Name:    d.Name,
+					Status:  StatusSkip,
+					Message: fmt.Sprintf("Skipped: %s failed", strings.Join(blocked, ", ")),
+				}}
+			} else {
+				checks = runOne(ctx, env, d)
+			}
+
+			mu.Lock()
+			results[i] = checks
+			for _, c := range checks {
+				if c.Status == StatusFail || c.Status == StatusSkip {
+					failed[d.ID] = true
+				}
+			}
+			mu.Unlock()
+		}(i, d)
+	}
+	wg.Wait()
+
+	report := NewReport()
+	for _, checks := range results {
+		report.AddAll(checks)
+	}
+	return report, nil
+}
+
+// runOne runs a check with its timeout. A check that ignores its context
+// is abandoned when the timeout expires.
+func runOne(ctx context.Context, env Env, d Definition) []Check {
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan []Check, 1)
+	go func() {
+		result <- d.Run(ctx, env)
+	}()
+
+	select {
+	case checks := <-result:
+		return checks
+	case <-ctx.Done():
+		return []Check{{
+			Name:    d.Name,
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("Check did not finish within %s", timeout),
+			Details: ctx.Err().Error(),
+		}}
+	}
+}
+
+// selected returns the definitions sel picks, plus their dependencies,
+// in report order.
+func (r *Registry) selected(sel Selection) ([]Definition, error) {
+	defs := r.sorted()
+
+	byID := map[string]Definition{}
+	for _, d := range defs {
+		byID[d.ID] = d
+	}
+
+	for _, id := range append(slices.Clone(sel.Only), sel.Skip...) {
+		if _, ok := byID[id]; !ok {
+			return nil, fmt.Errorf("unknown check %q (available: %s)", id, strings.Join(r.IDs(), ", "))
+		}
+	}
+
+	// Pull in dependencies of the checks asked for
+	want := map[string]bool{}
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		if slices.Contains(path, id) {
+			return fmt.Errorf("check dependency cycle: %s", strings.Join(append(path, id), " -> "))
+		}
+		d, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("check %q depends on unknown check %q", path[len(path)-1], id)
+		}
+		want[id] = true
+		for _, dep := range d.DependsOn {
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, d := range defs {
+		if len(sel.Only) == 0 || slices.Contains(sel.Only, d.ID) {
+			if err := visit(d.ID, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var result []Definition
+	for _, d := range defs {
+		if want[d.ID] && !slices.Contains(sel.Skip, d.ID) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// sorted returns the definitions ordered by category, then registration.
+func (r *Registry) sorted() []Definition {
+	r.mu.Lock()
+	defs := slices.Clone(r.defs)
+	r.mu.Unlock()
+
+	rank := func(category string) int {
+		if i := slices.Index(categoryOrder, category); i >= 0 {
+			return i
+		}
+		return len(categoryOrder)
+	}
+	sort.SliceStable(defs, func(i, j int) bool {
+		return rank(defs[i].Category) < rank(defs[j].Category)
+	})
+	return defs
+}
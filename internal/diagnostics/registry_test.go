@@ -0,0 +1,114 @@
+package diagnostics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubCheck(id, category string, status Status, deps ...string) Definition {
+	return Definition{
+		ID:        id,
+		Name:      id,
+		Category:  category,
+		DependsOn: deps,
+		Run: func(ctx context.Context, env Env) []Check {
+			return []Check{{Name: id, Status: status}}
+		},
+	}
+}
+
+func TestRegistryRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		sel     Selection
+		want    []string // Name=status pairs in report order
+		wantErr string
+	}{
+		{
+			name: "all checks in category order",
+			want: []string{"java=0", "paths=2", "product=3", "network=1"},
+		},
+		{
+			name: "only pulls in dependencies",
+			sel:  Selection{Only: []string{"product"}},
+			want: []string{"paths=2", "product=3"},
+		},
+		{
+			name: "skipped dependency doesn't block",
+			sel:  Selection{Skip: []string{"paths", "network"}},
+			want: []string{"java=0", "product=0"},
+		},
+		{
+			name:    "unknown check",
+			sel:     Selection{Only: []string{"jvm"}},
+			wantErr: `unknown check "jvm"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Registry{}
+			r.Register(stubCheck("network", CategoryNetwork, StatusWarn))
+			r.Register(stubCheck("product", CategoryProduct, StatusOK, "paths"))
+			r.Register(stubCheck("paths", CategoryPaths, StatusFail))
+			r.Register(stubCheck("java", CategoryRuntime, StatusOK))
+
+			report, err := r.Run(context.Background(), Env{}, tt.sel)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			var got []string
+			for _, c := range report.Checks {
+				got = append(got, c.Name+"="+string(rune('0'+c.Status)))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Run() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryRunTimeout(t *testing.T) {
+	r := &Registry{}
+	r.Register(Definition{
+		ID:      "slow",
+		Name:    "Slow",
+		Timeout: 10 * time.Millisecond,
+		Run: func(ctx context.Context, env Env) []Check {
+			time.Sleep(time.Second) // Ignores ctx
+			return []Check{{Name: "Slow", Status: StatusOK}}
+		},
+	})
+
+	start := time.Now()
+	report, err := r.Run(context.Background(), Env{}, Selection{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run() took %s, want it to stop at the timeout", elapsed)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Status != StatusWarn || !strings.Contains(report.Checks[0].Message, "did not finish") {
+		t.Errorf("Run() = %+v, want a timeout warning", report.Checks)
+	}
+}
+
+func TestRegistryRunCycle(t *testing.T) {
+	r := &Registry{}
+	r.Register(stubCheck("a", CategoryRuntime, StatusOK, "b"))
+	r.Register(stubCheck("b", CategoryRuntime, StatusOK, "a"))
+
+	_, err := r.Run(context.Background(), Env{}, Selection{})
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Run() error = %v, want dependency cycle", err)
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 // Report contains all diagnostic check results.
 type Report struct {
-	Checks []Check
+	Checks []Check `json:"checks"`
 }
 
 // NewReport creates a new empty report.
@@ -47,6 +47,43 @@ func (r *Report) HasWarnings() bool {
 	return false
 }
 
+// Fixes returns the remediations for checks that did not pass, without
+// duplicates.
+func (r *Report) Fixes() []Fix {
+	seen := map[Fix]bool{}
+	var fixes []Fix
+	for _, c := range r.Checks {
+		if c.Status == StatusOK || c.Fix == nil || seen[*c.Fix] {
+			continue
+		}
+		seen[*c.Fix] = true
+		fixes = append(fixes, *c.Fix)
+	}
+	return fixes
+}
+
+// PrintChanges outputs the checks whose status differs from before,
+// showing both results.
+func (r *Report) PrintChanges(w io.Writer, before *Report) {
+	previous := map[string]Check{}
+	for _, c := range before.Checks {
+		previous[c.Name] = c
+	}
+
+	changed := false
+	for _, c := range r.Checks {
+		old, ok := previous[c.Name]
+		if !ok || old.Status == c.Status {
+			continue
+		}
+		changed = true
+		fmt.Fprintf(w, "[%s] -> [%s] %s: %s\n", statusIcon(old.Status), statusIcon(c.Status), c.Name, c.Message)
+	}
+	if !changed {
+		fmt.Fprintln(w, "No checks changed")
+	}
+}
+
 // Print outputs the report to the given writer.
 func (r *Report) Print(w io.Writer) {
 	for _, c := range r.Checks {
@@ -77,6 +114,8 @@ func statusIcon(s Status) string {
 		return "!"
 	case StatusFail:
 		return "\u2717" // ✗
+	case StatusSkip:
+		return "-"
 	default:
 		return "?"
 	}
@@ -128,6 +128,154 @@ func ExtractZip(src, dest string) error {
 	return nil
 }
 
+// ArchiveSource is an entry added to an archive by CreateTarGz or CreateZip.
+type ArchiveSource struct {
+	Name string // Path inside the archive
+	Path string // File or directory to add
+	Data []byte // Content of a file entry, used instead of Path when set
+}
+
+// CreateTarGz writes sources to a new .tar.gz archive at dest. Directories
+// are added recursively; symlinks and special files are skipped. The
+// archive is written to a temporary file and renamed into place, so dest
+// is never left half-written.
+func CreateTarGz(dest string, sources []ArchiveSource) error {
+	tmp := dest + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+
+	for _, src := range sources {
+		if src.Data != nil {
+			err = addTarData(tw, src.Name, src.Data)
+		} else {
+			err = addTarPath(tw, src.Name, src.Path)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gzw.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return nil
+}
+
+// CreateZip writes file sources to a new zip archive at dest, replacing
+// it atomically like CreateTarGz. Only Data entries are supported.
+func CreateZip(dest string, sources []ArchiveSource) error {
+	tmp := dest + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	zw := zip.NewWriter(f)
+	for _, src := range sources {
+		if src.Data == nil {
+			err = fmt.Errorf("%s: zip entries must have data", src.Name)
+			break
+		}
+		var w io.Writer
+		if w, err = zw.Create(src.Name); err != nil {
+			break
+		}
+		if _, err = w.Write(src.Data); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return nil
+}
+
+func addTarData(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func addTarPath(tw *tar.Writer, name, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(name, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+}
+
 // sanitizePath ensures the file path doesn't escape the destination directory.
 // Prevents zip-slip vulnerability.
 func sanitizePath(dest, name string) (string, error) {
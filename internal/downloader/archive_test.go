@@ -305,3 +305,82 @@ func verifyExtractedFiles(t *testing.T, extractDir string) {
 		t.Errorf("file content = %q, want %q", string(content), "test content")
 	}
 }
+
+func TestCreateTarGz(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	if err := os.MkdirAll(filepath.Join(srcDir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "sub", "file.txt"), []byte("content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(srcDir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	archivePath := filepath.Join(tmpDir, "backup.tar.gz")
+	err := CreateTarGz(archivePath, []ArchiveSource{
+		{Name: "manifest.json", Data: []byte("{}")},
+		{Name: "state", Path: srcDir},
+	})
+	if err != nil {
+		t.Fatalf("CreateTarGz() error = %v", err)
+	}
+
+	extractDir := filepath.Join(tmpDir, "extracted")
+	if err := ExtractTarGz(archivePath, extractDir); err != nil {
+		t.Fatalf("ExtractTarGz() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"manifest.json":      "{}",
+		"state/sub/file.txt": "content",
+	} {
+		got, err := os.ReadFile(filepath.Join(extractDir, name))
+		if err != nil {
+			t.Errorf("read %s: %v", name, err)
+		} else if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(extractDir, "state", "link")); !os.IsNotExist(err) {
+		t.Errorf("symlink was archived, want it skipped")
+	}
+	if _, err := os.Stat(archivePath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary archive left behind")
+	}
+}
+
+func TestCreateZip(t *testing.T) {
+	tmpDir := t.TempDir()
+	archivePath := filepath.Join(tmpDir, "bundle.zip")
+
+	err := CreateZip(archivePath, []ArchiveSource{
+		{Name: "doctor.json", Data: []byte("{}")},
+		{Name: "logs/burpsuite.log", Data: []byte("started")},
+	})
+	if err != nil {
+		t.Fatalf("CreateZip() error = %v", err)
+	}
+
+	extractDir := filepath.Join(tmpDir, "extracted")
+	if err := ExtractZip(archivePath, extractDir); err != nil {
+		t.Fatalf("ExtractZip() error = %v", err)
+	}
+	for name, want := range map[string]string{
+		"doctor.json":        "{}",
+		"logs/burpsuite.log": "started",
+	} {
+		got, err := os.ReadFile(filepath.Join(extractDir, name))
+		if err != nil {
+			t.Errorf("read %s: %v", name, err)
+		} else if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	if err := CreateZip(archivePath, []ArchiveSource{{Name: "state", Path: tmpDir}}); err == nil {
+		t.Error("CreateZip() with a path entry succeeded, want error")
+	}
+}
@@ -0,0 +1,24 @@
+package downloader
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrOffline is returned for requests made while the network is disabled.
+var ErrOffline = errors.New("network access is disabled (--offline)")
+
+// offlineTransport fails every request with ErrOffline. The client adds
+// the method and URL to the error.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
+// DisableNetwork makes every request through http.DefaultTransport fail
+// with ErrOffline. relay's HTTP clients all use the default transport, so
+// this keeps the whole process offline.
+func DisableNetwork() {
+	http.DefaultTransport = offlineTransport{}
+}
@@ -90,15 +90,15 @@ func (p *ProgressBar) render() {
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", p.width-filled)
 
 	// Format sizes
-	currentStr := formatBytes(p.current)
-	totalStr := formatBytes(p.total)
+	currentStr := FormatBytes(p.current)
+	totalStr := FormatBytes(p.total)
 
 	// Calculate speed
 	elapsed := time.Since(p.started).Seconds()
 	var speedStr string
 	if elapsed > 0 {
 		speed := float64(p.current) / elapsed
-		speedStr = formatBytes(int64(speed)) + "/s"
+		speedStr = FormatBytes(int64(speed)) + "/s"
 	}
 
 	// Print progress bar (carriage return to overwrite)
@@ -112,8 +112,8 @@ func (p *ProgressBar) render() {
 	)
 }
 
-// formatBytes formats bytes as human-readable string.
-func formatBytes(b int64) string {
+// FormatBytes formats bytes as human-readable string.
+func FormatBytes(b int64) string {
 	const unit = 1024
 	if b < unit {
 		return fmt.Sprintf("%d B", b)
@@ -14,6 +14,11 @@ type DarwinShortcut struct {
 	homeDir string
 }
 
+// NewShortcut returns the desktop shortcut generator for this OS.
+func NewShortcut(cfg ShortcutConfig) (DesktopShortcut, error) {
+	return NewDarwinShortcut(cfg)
+}
+
 // NewDarwinShortcut creates a new macOS shortcut generator.
 func NewDarwinShortcut(cfg ShortcutConfig) (*DarwinShortcut, error) {
 	home, err := os.UserHomeDir()
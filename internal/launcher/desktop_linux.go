@@ -14,6 +14,11 @@ type LinuxShortcut struct {
 	homeDir string
 }
 
+// NewShortcut returns the desktop shortcut generator for this OS.
+func NewShortcut(cfg ShortcutConfig) (DesktopShortcut, error) {
+	return NewLinuxShortcut(cfg)
+}
+
 // NewLinuxShortcut creates a new Linux shortcut generator.
 func NewLinuxShortcut(cfg ShortcutConfig) (*LinuxShortcut, error) {
 	home, err := os.UserHomeDir()
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package launcher
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// NewShortcut reports that desktop shortcuts aren't supported on this OS.
+func NewShortcut(cfg ShortcutConfig) (DesktopShortcut, error) {
+	return nil, fmt.Errorf("desktop shortcuts are not supported on %s", runtime.GOOS)
+}
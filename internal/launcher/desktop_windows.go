@@ -16,6 +16,11 @@ type WindowsShortcut struct {
 	homeDir string
 }
 
+// NewShortcut returns the desktop shortcut generator for this OS.
+func NewShortcut(cfg ShortcutConfig) (DesktopShortcut, error) {
+	return NewWindowsShortcut(cfg)
+}
+
 // NewWindowsShortcut creates a new Windows shortcut generator.
 func NewWindowsShortcut(cfg ShortcutConfig) (*WindowsShortcut, error) {
 	home, err := os.UserHomeDir()
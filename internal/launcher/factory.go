@@ -11,9 +11,9 @@ import (
 func New(p plan.LaunchPlan, javaPath string) (Generator, error) {
 	switch runtime.GOOS {
 	case "linux", "darwin":
-		return ShellLauncher{BinDir: p.Paths.BinDir, JavaPath: javaPath}, nil
+		return ShellLauncher{BinDir: p.Paths.BinDir, JavaPath: javaPath, Name: p.Product}, nil
 	case "windows":
-		return PowerShellLauncher{BinDir: p.Paths.BinDir, JavaPath: javaPath}, nil
+		return PowerShellLauncher{BinDir: p.Paths.BinDir, JavaPath: javaPath, Name: p.Product}, nil
 	default:
 		return nil, fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
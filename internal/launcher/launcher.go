@@ -1,9 +1,32 @@
 package launcher
 
-import "github.com/sdmrf/relay/internal/plan"
+import (
+	"path/filepath"
+
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// defaultName names the launcher and its logs when no product is given.
+const defaultName = "burpsuite"
 
 // Generator creates platform-specific launchers.
 type Generator interface {
 	Generate(plan.LaunchPlan) error
 	Path() string
 }
+
+// baseName returns the name of the launcher and its log and PID files.
+func baseName(name string) string {
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
+
+// jarPath returns the JAR the launcher runs.
+func jarPath(p plan.LaunchPlan) string {
+	if p.Jar != "" {
+		return p.Jar
+	}
+	return filepath.Join(p.Paths.InstallDir, defaultName+".jar")
+}
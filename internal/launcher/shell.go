@@ -10,17 +10,24 @@ import (
 )
 
 const shellTemplate = `#!/bin/sh
+{{- if .StateDir}}
+mkdir -p "{{.StateDir}}"
+exec "{{.JavaPath}}" {{range .JVMArgs}}{{.}} {{end}}-jar "{{.JarPath}}" "$@" >>"{{.StateDir}}/{{.Name}}.log" 2>&1 &
+echo $! >"{{.StateDir}}/{{.Name}}.pid"
+{{- else}}
 exec "{{.JavaPath}}" {{range .JVMArgs}}{{.}} {{end}}-jar "{{.JarPath}}" "$@" &
+{{- end}}
 `
 
 // ShellLauncher generates shell scripts for Linux/macOS.
 type ShellLauncher struct {
 	BinDir   string
 	JavaPath string
+	Name     string // Product name for the script and logs; empty means burpsuite
 }
 
 func (s ShellLauncher) Path() string {
-	return filepath.Join(s.BinDir, "burpsuite")
+	return filepath.Join(s.BinDir, baseName(s.Name))
 }
 
 func (s ShellLauncher) Generate(p plan.LaunchPlan) error {
@@ -39,10 +46,14 @@ func (s ShellLauncher) Generate(p plan.LaunchPlan) error {
 		JavaPath string
 		JVMArgs  []string
 		JarPath  string
+		StateDir string
+		Name     string
 	}{
 		JavaPath: s.JavaPath,
 		JVMArgs:  p.JVMArgs,
-		JarPath:  filepath.Join(p.Paths.InstallDir, "burpsuite.jar"),
+		JarPath:  jarPath(p),
+		StateDir: p.Paths.StateDir,
+		Name:     baseName(s.Name),
 	}
 
 	if err := t.Execute(f, data); err != nil {
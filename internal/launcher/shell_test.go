@@ -130,6 +130,38 @@ func TestShellLauncherGenerateEmptyJVMArgs(t *testing.T) {
 	}
 }
 
+func TestShellLauncherGenerateStateDir(t *testing.T) {
+	s := ShellLauncher{BinDir: t.TempDir(), JavaPath: "/usr/bin/java"}
+
+	p := plan.LaunchPlan{
+		Product: "burpsuite",
+		Paths: plan.Paths{
+			InstallDir: "/opt/relay",
+			StateDir:   "/home/user/.local/state/relay",
+		},
+	}
+
+	if err := s.Generate(p); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content, err := os.ReadFile(s.Path())
+	if err != nil {
+		t.Fatalf("failed to read generated launcher: %v", err)
+	}
+
+	script := string(content)
+	for _, want := range []string{
+		`mkdir -p "/home/user/.local/state/relay"`,
+		`>>"/home/user/.local/state/relay/burpsuite.log" 2>&1 &`,
+		`echo $! >"/home/user/.local/state/relay/burpsuite.pid"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("launcher script should contain %q, got: %s", want, script)
+		}
+	}
+}
+
 func TestNewLauncher(t *testing.T) {
 	p := plan.LaunchPlan{
 		Product: "burpsuite",
@@ -156,3 +188,38 @@ func TestNewLauncher(t *testing.T) {
 		t.Errorf("generator path should be in bin dir, got %v", filepath.Dir(path))
 	}
 }
+
+func TestShellLauncherGenerateProduct(t *testing.T) {
+	s := ShellLauncher{BinDir: t.TempDir(), JavaPath: "/usr/bin/java", Name: "zap"}
+
+	p := plan.LaunchPlan{
+		Product: "zap",
+		Jar:     "/opt/relay/ZAP_2.15.0/zap-2.15.0.jar",
+		Paths: plan.Paths{
+			InstallDir: "/opt/relay",
+			StateDir:   "/home/user/.local/state/relay",
+		},
+	}
+
+	if err := s.Generate(p); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if filepath.Base(s.Path()) != "zap" {
+		t.Errorf("Path() = %s, want a launcher named zap", s.Path())
+	}
+
+	content, err := os.ReadFile(s.Path())
+	if err != nil {
+		t.Fatalf("failed to read generated launcher: %v", err)
+	}
+
+	script := string(content)
+	for _, want := range []string{
+		`-jar "/opt/relay/ZAP_2.15.0/zap-2.15.0.jar"`,
+		`>>"/home/user/.local/state/relay/zap.log" 2>&1 &`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("launcher script should contain %q, got: %s", want, script)
+		}
+	}
+}
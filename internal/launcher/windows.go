@@ -9,17 +9,21 @@ import (
 	"github.com/sdmrf/relay/internal/plan"
 )
 
-const psTemplate = `Start-Process "{{.JavaPath}}" -ArgumentList '{{range .JVMArgs}}{{.}} {{end}}-jar "{{.JarPath}}"' -NoNewWindow
-`
+const psTemplate = `{{if .StateDir}}New-Item -ItemType Directory -Force -Path "{{.StateDir}}" | Out-Null
+$p = Start-Process "{{.JavaPath}}" -ArgumentList '{{range .JVMArgs}}{{.}} {{end}}-jar "{{.JarPath}}"' -NoNewWindow -PassThru -RedirectStandardOutput "{{.StateDir}}\{{.Name}}.log" -RedirectStandardError "{{.StateDir}}\{{.Name}}.err.log"
+Set-Content -Path "{{.StateDir}}\{{.Name}}.pid" -Value $p.Id
+{{else}}Start-Process "{{.JavaPath}}" -ArgumentList '{{range .JVMArgs}}{{.}} {{end}}-jar "{{.JarPath}}"' -NoNewWindow
+{{end}}`
 
 // PowerShellLauncher generates PowerShell scripts for Windows.
 type PowerShellLauncher struct {
 	BinDir   string
 	JavaPath string
+	Name     string // Product name for the script and logs; empty means burpsuite
 }
 
 func (p PowerShellLauncher) Path() string {
-	return filepath.Join(p.BinDir, "burpsuite.ps1")
+	return filepath.Join(p.BinDir, baseName(p.Name)+".ps1")
 }
 
 func (p PowerShellLauncher) Generate(lp plan.LaunchPlan) error {
@@ -38,10 +42,14 @@ func (p PowerShellLauncher) Generate(lp plan.LaunchPlan) error {
 		JavaPath string
 		JVMArgs  []string
 		JarPath  string
+		StateDir string
+		Name     string
 	}{
 		JavaPath: p.JavaPath,
 		JVMArgs:  lp.JVMArgs,
-		JarPath:  filepath.Join(lp.Paths.InstallDir, "burpsuite.jar"),
+		JarPath:  jarPath(lp),
+		StateDir: lp.Paths.StateDir,
+		Name:     baseName(p.Name),
 	}
 
 	if err := t.Execute(f, data); err != nil {
@@ -0,0 +1,33 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Writable reports whether the current user can create files in dir, or
+// create dir itself when it does not exist yet.
+func Writable(dir string) bool {
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return false
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".relay-write-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
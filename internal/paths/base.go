@@ -14,8 +14,8 @@ func baseDirs(currentOS OS) (config, cache string, err error) {
 
 	switch currentOS {
 	case Linux:
-		config = filepath.Join(home, ".config", "relay")
-		cache = filepath.Join(home, ".cache", "relay")
+		config = filepath.Join(xdgDir("XDG_CONFIG_HOME", home, ".config"), "relay")
+		cache = filepath.Join(xdgDir("XDG_CACHE_HOME", home, ".cache"), "relay")
 	case Darwin:
 		config = filepath.Join(home, "Library", "Application Support", "relay")
 		cache = filepath.Join(home, "Library", "Caches", "relay")
@@ -36,3 +36,13 @@ func baseDirs(currentOS OS) (config, cache string, err error) {
 func osUserHome() (string, error) {
 	return os.UserHomeDir()
 }
+
+// xdgDir returns the XDG base directory named by env, or home joined with
+// the fallback elements. Per the XDG Base Directory spec, relative values
+// are invalid and ignored.
+func xdgDir(env, home string, fallback ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(append([]string{home}, fallback...)...)
+}
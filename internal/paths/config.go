@@ -0,0 +1,34 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ConfigFileName is the config file name in each config location.
+const ConfigFileName = "config.yaml"
+
+// SystemConfigFile returns the machine-wide config file, shared by all users.
+func SystemConfigFile() string {
+	switch CurrentOS() {
+	case Darwin:
+		return filepath.Join("/Library", "Application Support", "relay", ConfigFileName)
+	case Windows:
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			return ""
+		}
+		return filepath.Join(programData, "relay", ConfigFileName)
+	default:
+		return filepath.Join("/etc", "relay", ConfigFileName)
+	}
+}
+
+// UserConfigFile returns the per-user config file in the config directory.
+func UserConfigFile() (string, error) {
+	configDir, _, err := baseDirs(CurrentOS())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, ConfigFileName), nil
+}
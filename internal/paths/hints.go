@@ -0,0 +1,64 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// windowsEnvPattern matches %VAR% references in Windows paths.
+var windowsEnvPattern = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+// expandHint turns a path hint from the config into an absolute path.
+// "" and "auto" mean no hint and return "". A leading ~ is the user's home;
+// $VAR and ${VAR} (and %VAR% on Windows) are expanded, and referencing an
+// unset variable is an error rather than silently producing another path.
+func expandHint(name, hint string) (string, error) {
+	hint = strings.TrimSpace(hint)
+	if hint == "" || hint == "auto" {
+		return "", nil
+	}
+
+	var missing []string
+	lookup := func(v string) string {
+		value, ok := os.LookupEnv(v)
+		if !ok {
+			missing = append(missing, v)
+		}
+		return value
+	}
+
+	expanded := os.Expand(hint, lookup)
+	if CurrentOS() == Windows {
+		expanded = windowsEnvPattern.ReplaceAllStringFunc(expanded, func(m string) string {
+			return lookup(strings.Trim(m, "%"))
+		})
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%s path %q: environment variable %s is not set", name, hint, strings.Join(missing, ", "))
+	}
+
+	if expanded == "~" || strings.HasPrefix(expanded, "~/") || strings.HasPrefix(expanded, `~\`) {
+		home, err := osUserHome()
+		if err != nil {
+			return "", fmt.Errorf("%s path %q: %w", name, hint, err)
+		}
+		expanded = filepath.Join(home, expanded[1:])
+	}
+
+	if !filepath.IsAbs(expanded) {
+		return "", fmt.Errorf("%s path must be absolute: %s", name, hint)
+	}
+
+	return filepath.Clean(expanded), nil
+}
+
+// orDefault returns hint when set, otherwise def.
+func orDefault(hint, def string) string {
+	if hint != "" {
+		return hint
+	}
+	return def
+}
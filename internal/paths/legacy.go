@@ -0,0 +1,140 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Relocation is a relay directory found at a location the current
+// layout no longer uses.
+type Relocation struct {
+	Name string // "install", "config" or "cache"
+	From string
+	To   string
+	Keep []string // Entries of From that stay; the rest move into To
+}
+
+// FindLegacy returns relay directories left where an older release put
+// them: the pre-XDG Linux defaults (~/.local/share, ~/.config, ~/.cache)
+// when XDG variables now resolve p elsewhere, and a macOS install in the
+// config directory. A directory is only reported when its new location is
+// missing or empty, so nothing is ever merged or overwritten.
+func FindLegacy(p Paths, opts Options) ([]Relocation, error) {
+	if opts.Layout != SystemLayout {
+		return nil, nil
+	}
+
+	home, err := osUserHome()
+	if err != nil {
+		return nil, err
+	}
+
+	switch CurrentOS() {
+	case Linux:
+		return findXDGLegacy(p, opts, home), nil
+	case Darwin:
+		return findDarwinLegacy(p, opts, home), nil
+	}
+	return nil, nil
+}
+
+// findXDGLegacy finds relay directories at the pre-XDG Linux defaults.
+func findXDGLegacy(p Paths, opts Options, home string) []Relocation {
+
+	candidates := []Relocation{
+		{Name: "config", From: filepath.Join(home, ".config", "relay"), To: p.ConfigDir},
+		{Name: "cache", From: filepath.Join(home, ".cache", "relay"), To: p.CacheDir},
+	}
+	if hint, _ := expandHint("install", opts.InstallHint); hint == "" {
+		candidates = append(candidates, Relocation{Name: "install", From: filepath.Join(home, ".local", "share", "relay"), To: p.InstallDir})
+	}
+
+	var found []Relocation
+	for _, r := range candidates {
+		if filepath.Clean(r.From) == filepath.Clean(r.To) || !isDir(r.From) || !isEmptyOrMissing(r.To) {
+			continue
+		}
+		found = append(found, r)
+	}
+	return found
+}
+
+// findDarwinLegacy finds a macOS install made when the install directory
+// was the config directory itself. Everything but the config file and
+// backups moves into the install directory.
+func findDarwinLegacy(p Paths, opts Options, home string) []Relocation {
+	if hint, _ := expandHint("install", opts.InstallHint); hint != "" {
+		return nil
+	}
+
+	from := p.ConfigDir
+	to := darwinInstall(home)
+	if (!HasMarker(from) && !isFile(filepath.Join(from, legacyVersionFile))) || !isEmptyOrMissing(to) {
+		return nil
+	}
+	return []Relocation{{Name: "install", From: from, To: to, Keep: []string{ConfigFileName, "backups", filepath.Base(to)}}}
+}
+
+// Relocate moves a legacy directory to its new location. Moves across
+// filesystems are not supported; the error says which directory to move.
+func Relocate(r Relocation) error {
+	if len(r.Keep) > 0 {
+		return relocateEntries(r)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.To), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(r.To), err)
+	}
+
+	// An empty target left by an earlier run would make Rename fail
+	if err := os.Remove(r.To); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("move %s directory: %s is not empty", r.Name, r.To)
+	}
+
+	if err := os.Rename(r.From, r.To); err != nil {
+		return fmt.Errorf("move %s directory from %s to %s: %w", r.Name, r.From, r.To, err)
+	}
+	return nil
+}
+
+// relocateEntries moves the entries of r.From not in r.Keep into r.To.
+func relocateEntries(r Relocation) error {
+	entries, err := os.ReadDir(r.From)
+	if err != nil {
+		return fmt.Errorf("move %s directory: %w", r.Name, err)
+	}
+	if err := os.MkdirAll(r.To, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", r.To, err)
+	}
+
+	for _, e := range entries {
+		if slices.Contains(r.Keep, e.Name()) {
+			continue
+		}
+		from, to := filepath.Join(r.From, e.Name()), filepath.Join(r.To, e.Name())
+		if err := os.Rename(from, to); err != nil {
+			return fmt.Errorf("move %s directory from %s to %s: %w", r.Name, from, to, err)
+		}
+	}
+	return nil
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func isEmptyOrMissing(path string) bool {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && len(entries) == 0
+}
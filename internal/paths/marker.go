@@ -0,0 +1,186 @@
+package paths
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// OwnershipMarker is the file relay writes into each directory it creates.
+// Nothing is deleted or overwritten in a directory without one.
+const OwnershipMarker = ".relay-owned"
+
+// Marker identifies the installation that created a directory.
+type Marker struct {
+	InstallID string // Shared by every directory of one installation
+	Layout    Layout
+}
+
+// NewInstallID returns a random installation ID.
+func NewInstallID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate install ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ReadMarker reads the ownership marker in dir. Markers written by older
+// relay versions, or created by hand, may be empty.
+func ReadMarker(dir string) (Marker, error) {
+	f, err := os.Open(filepath.Join(dir, OwnershipMarker))
+	if err != nil {
+		return Marker{}, err
+	}
+	defer f.Close()
+
+	var m Marker
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "install_id":
+			m.InstallID = strings.TrimSpace(value)
+		case "layout":
+			m.Layout = Layout(strings.TrimSpace(value))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Marker{}, fmt.Errorf("read ownership marker: %w", err)
+	}
+	return m, nil
+}
+
+// WriteMarker marks dir as created by relay.
+func WriteMarker(dir string, m Marker) error {
+	content := fmt.Sprintf("# Created by relay. 'relay remove' deletes this directory.\ninstall_id: %s\nlayout: %s\n", m.InstallID, m.Layout)
+	if err := os.WriteFile(filepath.Join(dir, OwnershipMarker), []byte(content), 0o644); err != nil {
+		return fmt.Errorf("write ownership marker: %w", err)
+	}
+	return nil
+}
+
+// HasMarker reports whether dir carries relay's ownership marker.
+func HasMarker(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, OwnershipMarker))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// cacheFiles are the files relay caches outside an install (see
+// runtime.ProbeCacheFile). A directory holding only these is still relay's.
+var cacheFiles = []string{"java-probe.json", "java-probe.json.tmp"}
+
+// legacyVersionFile is the version marker every relay install has held
+// (see product.VersionMarkerFile). Installs made before ownership markers
+// existed are recognised by it and adopted when claimed again.
+const legacyVersionFile = ".relay-version"
+
+// cacheOnly reports whether entries are all files relay caches.
+func cacheOnly(entries []os.DirEntry) bool {
+	for _, e := range entries {
+		if !slices.Contains(cacheFiles, e.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// legacyInstall reports whether entries are a pre-marker relay install.
+func legacyInstall(entries []os.DirEntry) bool {
+	return slices.ContainsFunc(entries, func(e os.DirEntry) bool {
+		return e.Name() == legacyVersionFile && e.Type().IsRegular()
+	})
+}
+
+// CheckClaim reports whether Claim may take dir: it must not be protected,
+// and must be missing, empty, already marked, hold only relay's cache, or
+// be a pre-marker relay install.
+func CheckClaim(dir string) error {
+	if Protected(dir) {
+		return protectedError(dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) || HasMarker(dir) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cacheOnly(entries) || legacyInstall(entries) {
+		return nil
+	}
+	return fmt.Errorf("%s already exists and was not created by relay (no %s marker); choose an empty directory, or create an empty %s file in it if an older relay version installed it", dir, OwnershipMarker, OwnershipMarker)
+}
+
+// Claim creates dir if needed and marks it as relay's. A directory that
+// is already marked keeps its marker.
+func Claim(dir string, m Marker) error {
+	if err := CheckClaim(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory %s: %w", dir, err)
+	}
+	if HasMarker(dir) {
+		return nil
+	}
+	return WriteMarker(dir, m)
+}
+
+// MarkerFor returns the marker for directories of the installation at
+// installDir, reusing its install ID when it has one.
+func MarkerFor(installDir string, layout Layout) (Marker, error) {
+	m := Marker{Layout: layout}
+	if existing, err := ReadMarker(installDir); err == nil && existing.InstallID != "" {
+		m.InstallID = existing.InstallID
+		return m, nil
+	}
+	id, err := NewInstallID()
+	if err != nil {
+		return Marker{}, err
+	}
+	m.InstallID = id
+	return m, nil
+}
+
+// ClaimCache claims cacheDir before relay caches files in it outside an
+// install, so the install can still claim it later.
+func ClaimCache(cacheDir, installDir string, layout Layout) error {
+	m, err := MarkerFor(installDir, layout)
+	if err != nil {
+		return err
+	}
+	return Claim(cacheDir, m)
+}
+
+// CheckOwned returns an error unless relay may delete or overwrite files
+// in dir: it must not be protected and must carry a marker, or hold only
+// relay's cache. Missing directories are fine. Pre-marker installs are
+// not adopted here; they are refused until claimed again by an install.
+func CheckOwned(dir string) error {
+	if Protected(dir) {
+		return protectedError(dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) || HasMarker(dir) {
+		return nil
+	}
+	if err == nil && len(entries) > 0 && cacheOnly(entries) {
+		return nil
+	}
+	return fmt.Errorf("refusing to modify %s: it has no %s marker, so relay may not have created it; run 'relay install' first if an older relay version installed it, or delete it yourself if it is no longer needed", dir, OwnershipMarker)
+}
+
+func protectedError(dir string) error {
+	return fmt.Errorf("refusing to modify %s: it is a protected system or home directory", dir)
+}
@@ -0,0 +1,175 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClaim(t *testing.T) {
+	root := t.TempDir()
+	m := Marker{InstallID: "0123456789abcdef", Layout: SystemLayout}
+
+	// Missing directories are created and marked
+	fresh := filepath.Join(root, "fresh")
+	if err := Claim(fresh, m); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	got, err := ReadMarker(fresh)
+	if err != nil {
+		t.Fatalf("ReadMarker() error = %v", err)
+	}
+	if got != m {
+		t.Errorf("ReadMarker() = %+v, want %+v", got, m)
+	}
+
+	// Marked directories keep their marker
+	if err := Claim(fresh, Marker{InstallID: "other"}); err != nil {
+		t.Fatalf("Claim() on marked dir error = %v", err)
+	}
+	if got, _ := ReadMarker(fresh); got.InstallID != m.InstallID {
+		t.Errorf("Claim() replaced marker: %+v", got)
+	}
+
+	// Existing unmarked directories with content are refused
+	foreign := filepath.Join(root, "foreign")
+	if err := os.MkdirAll(foreign, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(foreign, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Claim(foreign, m); err == nil {
+		t.Error("Claim() on unmarked dir with content succeeded")
+	}
+	if HasMarker(foreign) {
+		t.Error("Claim() marked a foreign directory")
+	}
+}
+
+func TestReadMarkerEmpty(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, OwnershipMarker), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := ReadMarker(dir)
+	if err != nil {
+		t.Fatalf("ReadMarker() error = %v", err)
+	}
+	if m != (Marker{}) {
+		t.Errorf("ReadMarker() = %+v, want empty marker", m)
+	}
+}
+
+func TestCheckOwned(t *testing.T) {
+	owned := t.TempDir()
+	if err := WriteMarker(owned, Marker{InstallID: "id"}); err != nil {
+		t.Fatalf("WriteMarker() error = %v", err)
+	}
+	foreign := t.TempDir()
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	for _, dir := range []string{owned, missing} {
+		if err := CheckOwned(dir); err != nil {
+			t.Errorf("CheckOwned(%s) error = %v, want nil", dir, err)
+		}
+	}
+
+	err := CheckOwned(foreign)
+	if err == nil || !strings.Contains(err.Error(), foreign) {
+		t.Errorf("CheckOwned() error = %v, want refusal for %s", err, foreign)
+	}
+}
+
+func TestProtected(t *testing.T) {
+	if CurrentOS() != Linux {
+		t.Skip("paths are Linux paths")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "/srv/xdg")
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"/", true},
+		{"/usr", true},
+		{"/usr/local/bin/", true},
+		{"/etc", true},
+		{home, true},
+		{filepath.Join(home, "Documents"), true},
+		{filepath.Join(home, ".local", "share"), true},
+		{"/srv/xdg", true},
+		{filepath.Join(home, ".local", "share", "relay"), false},
+		{"/opt/relay", false},
+		{"/srv/xdg/relay", false},
+	}
+
+	for _, tt := range tests {
+		if got := Protected(tt.dir); got != tt.want {
+			t.Errorf("Protected(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestUnmarkedDirs(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		claim bool // CheckClaim accepts it
+		owned bool // CheckOwned accepts it
+	}{
+		{"empty", nil, true, false},
+		{"probe cache", []string{"java-probe.json"}, true, true},
+		{"pre-marker install", []string{".relay-version", "burpsuite.jar", "notes.txt"}, true, false},
+		{"jar only", []string{"burpsuite.jar"}, false, false},
+		{"foreign", []string{"java-probe.json", "notes.txt"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := CheckClaim(dir); (err == nil) != tt.claim {
+				t.Errorf("CheckClaim() error = %v, want accepted %v", err, tt.claim)
+			}
+			if err := CheckOwned(dir); (err == nil) != tt.owned {
+				t.Errorf("CheckOwned() error = %v, want accepted %v", err, tt.owned)
+			}
+		})
+	}
+}
+
+func TestClaimCache(t *testing.T) {
+	root := t.TempDir()
+	install := filepath.Join(root, "install")
+	cache := filepath.Join(root, "cache")
+
+	if err := ClaimCache(cache, install, SystemLayout); err != nil {
+		t.Fatalf("ClaimCache() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cache, "java-probe.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The install that follows still claims the cache directory
+	m, err := MarkerFor(install, SystemLayout)
+	if err != nil {
+		t.Fatalf("MarkerFor() error = %v", err)
+	}
+	if err := Claim(cache, m); err != nil {
+		t.Errorf("Claim() after ClaimCache error = %v", err)
+	}
+	if err := Claim(install, m); err != nil {
+		t.Errorf("Claim() install error = %v", err)
+	}
+}
@@ -10,8 +10,11 @@ const (
 	Windows OS = "windows"
 )
 
+// goos is the operating system paths are resolved for; tests override it.
+var goos = runtime.GOOS
+
 func CurrentOS() OS {
-	switch runtime.GOOS {
+	switch goos {
 	case "linux":
 		return Linux
 	case "darwin":
@@ -19,6 +22,6 @@ func CurrentOS() OS {
 	case "windows":
 		return Windows
 	default:
-		return OS(runtime.GOOS)
+		return OS(goos)
 	}
 }
@@ -1,12 +1,78 @@
 package paths
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
 // Owned returns paths that relay owns and can safely modify/delete.
 // Excludes ConfigDir to preserve user configuration.
 // Excludes BinDir as it may be a shared system directory (e.g., /usr/local/bin).
+// Excludes a shared InstallDir, which other users still need.
 func (p Paths) Owned() []string {
+	if p.Shared {
+		return []string{p.DataDir, p.CacheDir, p.StateDir}
+	}
 	return []string{
 		p.InstallDir,
 		p.DataDir,
 		p.CacheDir,
+		p.StateDir,
+	}
+}
+
+// checkOverlap rejects directory combinations where remove, which deletes
+// the owned directories, would take more than relay's own files with it.
+func (p Paths) checkOverlap(layout Layout) error {
+	home, _ := osUserHome()
+
+	owned := []struct {
+		name string
+		dir  string
+	}{
+		{"install", p.InstallDir},
+		{"data", p.DataDir},
+		{"cache", p.CacheDir},
+		{"state", p.StateDir},
+	}
+
+	for _, o := range owned {
+		if filepath.Dir(o.dir) == o.dir {
+			return fmt.Errorf("%s directory %s is a filesystem root", o.name, o.dir)
+		}
+		if home != "" && Within(home, o.dir) {
+			return fmt.Errorf("%s directory %s contains your home directory", o.name, o.dir)
+		}
+
+		// The launcher may live in a shared directory such as ~/bin;
+		// only a bin directory inside the install dir is relay's to delete
+		if o.dir == p.BinDir || (o.name != "install" && Within(p.BinDir, o.dir)) {
+			return fmt.Errorf("bin directory %s is inside the %s directory %s, which remove deletes", p.BinDir, o.name, o.dir)
+		}
+
+		// The user's config survives remove outside the portable layout
+		if layout != PortableLayout && Within(p.ConfigDir, o.dir) {
+			return fmt.Errorf("config directory %s is inside the %s directory %s, which remove deletes", p.ConfigDir, o.name, o.dir)
+		}
+
+		if Within(p.BackupDir, o.dir) {
+			return fmt.Errorf("backup directory %s is inside the %s directory %s, which remove deletes", p.BackupDir, o.name, o.dir)
+		}
+
+		if Protected(o.dir) {
+			return fmt.Errorf("%s directory %s is a protected system or home directory", o.name, o.dir)
+		}
+	}
+
+	return nil
+}
+
+// Within reports whether path is dir or inside it.
+func Within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
 	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
 }
@@ -5,6 +5,7 @@ type Layout string
 const (
 	SystemLayout   Layout = "system"
 	PortableLayout Layout = "portable"
+	SharedLayout   Layout = "shared"
 )
 
 type Paths struct {
@@ -13,4 +14,8 @@ type Paths struct {
 	BinDir     string
 	ConfigDir  string
 	CacheDir   string
+	StateDir   string // Logs and PID files
+	Shared     bool   // InstallDir is machine-wide and managed by an admin
+	Layout     Layout
+	BackupDir  string // Archives written by relay backup; never removed
 }
@@ -0,0 +1,88 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// protectedSystem lists system directories relay never deletes or writes
+// into, whatever the config says.
+var protectedSystem = map[OS][]string{
+	Linux: {
+		"/", "/bin", "/boot", "/dev", "/etc", "/home", "/lib", "/lib32", "/lib64",
+		"/media", "/mnt", "/opt", "/proc", "/root", "/run", "/sbin", "/srv",
+		"/sys", "/tmp", "/usr", "/usr/bin", "/usr/lib", "/usr/local",
+		"/usr/local/bin", "/usr/local/share", "/usr/sbin", "/usr/share", "/var",
+		"/var/lib", "/var/tmp",
+	},
+	Darwin: {
+		"/", "/Applications", "/Library", "/Library/Application Support",
+		"/System", "/Users", "/Volumes", "/bin", "/etc", "/opt", "/private",
+		"/sbin", "/tmp", "/usr", "/usr/bin", "/usr/local", "/usr/local/bin",
+		"/var",
+	},
+}
+
+// protectedHome lists directories under the user's home that hold other
+// programs' files.
+var protectedHome = []string{
+	"bin", "Desktop", "Documents", "Downloads", "Music", "Pictures", "Videos",
+	".cache", ".config", ".local", filepath.Join(".local", "bin"),
+	filepath.Join(".local", "share"), filepath.Join(".local", "state"), ".ssh",
+	"Library", filepath.Join("Library", "Application Support"),
+	filepath.Join("Library", "Caches"), "AppData", filepath.Join("AppData", "Local"),
+	filepath.Join("AppData", "Roaming"),
+}
+
+// protectedEnv names environment variables holding directories relay
+// never deletes or writes into.
+var protectedEnv = []string{
+	"HOME", "USERPROFILE", "XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME",
+	"XDG_STATE_HOME", "SystemDrive", "SystemRoot", "windir", "ProgramFiles",
+	"ProgramFiles(x86)", "ProgramData", "APPDATA", "LOCALAPPDATA",
+}
+
+// Protected reports whether dir is a critical system or home directory,
+// which relay refuses to delete or write into even when configured to.
+func Protected(dir string) bool {
+	dir = filepath.Clean(dir)
+	if filepath.Dir(dir) == dir {
+		return true // Filesystem or drive root
+	}
+
+	for _, p := range protectedPaths() {
+		if samePath(dir, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func protectedPaths() []string {
+	list := append([]string{}, protectedSystem[CurrentOS()]...)
+
+	if home, err := osUserHome(); err == nil && home != "" {
+		list = append(list, home)
+		for _, rel := range protectedHome {
+			list = append(list, filepath.Join(home, rel))
+		}
+	}
+
+	for _, env := range protectedEnv {
+		if v := os.Getenv(env); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// samePath compares paths the way the OS does: case-insensitively on
+// macOS and Windows.
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if CurrentOS() == Linux {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
@@ -10,8 +10,12 @@ type Options struct {
 	InstallHint string
 	DataHint    string
 	BinHint     string
+	BackupHint  string
+	Name        string // Named install; its unhinted directories get their own subdirectory
 }
 
+// Resolve computes directories for the layout. Hints override the
+// defaults after ~ and environment-variable expansion; see expandHint.
 func Resolve(opts Options) (Paths, error) {
 	os := CurrentOS()
 
@@ -20,14 +24,51 @@ func Resolve(opts Options) (Paths, error) {
 		return Paths{}, err
 	}
 
+	hints := []struct {
+		name string
+		hint *string
+	}{
+		{"install", &opts.InstallHint},
+		{"data", &opts.DataHint},
+		{"bin", &opts.BinHint},
+		{"backup", &opts.BackupHint},
+	}
+	for _, h := range hints {
+		if *h.hint, err = expandHint(h.name, *h.hint); err != nil {
+			return Paths{}, err
+		}
+	}
+
+	var p Paths
 	switch opts.Layout {
 	case SystemLayout:
-		return resolveSystem(os, configDir, cacheDir, opts)
+		p, err = resolveSystem(os, configDir, cacheDir, opts)
 	case PortableLayout:
-		return resolvePortable(configDir, cacheDir, opts)
+		p, err = resolvePortable(configDir, cacheDir, opts)
+	case SharedLayout:
+		p, err = resolveShared(os, configDir, cacheDir, opts)
 	default:
 		return Paths{}, fmt.Errorf("invalid layout: %s", opts.Layout)
 	}
+	if err != nil {
+		return Paths{}, err
+	}
+	if opts.Name != "" {
+		p = p.named(opts)
+	}
+
+	// Backups must outlive remove, which takes the whole portable root
+	backupDir := filepath.Join(p.ConfigDir, "backups")
+	if opts.Layout == PortableLayout {
+		backupDir = p.InstallDir + "-backups"
+	}
+	p.BackupDir = orDefault(opts.BackupHint, backupDir)
+
+	if err := p.checkOverlap(opts.Layout); err != nil {
+		return Paths{}, err
+	}
+	p.Layout = opts.Layout
+	return p, nil
 }
 
 func resolveSystem(os OS, configDir, cacheDir string, opts Options) (Paths, error) {
@@ -36,28 +77,42 @@ func resolveSystem(os OS, configDir, cacheDir string, opts Options) (Paths, erro
 		return Paths{}, err
 	}
 
-	var install, data string
+	var install, data, state string
 
 	switch os {
 	case Linux:
-		// User-writable locations
-		install = filepath.Join(home, ".local", "share", "relay")
-		data = filepath.Join(home, ".local", "share", "relay", "data")
+		// User-writable XDG locations
+		install = filepath.Join(xdgDir("XDG_DATA_HOME", home, ".local", "share"), "relay")
+		data = filepath.Join(install, "data")
+		state = filepath.Join(xdgDir("XDG_STATE_HOME", home, ".local", "state"), "relay")
 	case Darwin:
-		// User-writable locations in ~/Library
-		install = filepath.Join(home, "Library", "Application Support", "relay")
-		data = filepath.Join(home, "Library", "Application Support", "relay", "data")
+		// User-writable locations in ~/Library, beside the config
+		// directory rather than around it, since remove deletes them
+		install = darwinInstall(home)
+		data = filepath.Join(install, "data")
+		state = filepath.Join(install, "state")
 	case Windows:
 		// User's local app data
 		localAppData := filepath.Join(home, "AppData", "Local")
 		install = filepath.Join(localAppData, "relay")
 		data = filepath.Join(localAppData, "relay", "data")
+		state = filepath.Join(localAppData, "relay", "state")
 	default:
 		return Paths{}, fmt.Errorf("unsupported OS: %s", os)
 	}
 
+	// Data and bin follow a custom install dir unless hinted separately
+	if opts.InstallHint != "" {
+		install = opts.InstallHint
+		data = filepath.Join(install, "data")
+		if os != Linux {
+			state = filepath.Join(install, "state")
+		}
+	}
+	data = orDefault(opts.DataHint, data)
+
 	// Bin directory inside install dir - no sudo needed for launcher
-	bin := filepath.Join(install, "bin")
+	bin := orDefault(opts.BinHint, filepath.Join(install, "bin"))
 
 	return Paths{
 		InstallDir: install,
@@ -65,22 +120,92 @@ func resolveSystem(os OS, configDir, cacheDir string, opts Options) (Paths, erro
 		BinDir:     bin,
 		ConfigDir:  configDir,
 		CacheDir:   cacheDir,
+		StateDir:   state,
 	}, nil
 }
 
+// darwinInstall returns the macOS system layout's install directory.
+func darwinInstall(home string) string {
+	return filepath.Join(home, "Library", "Application Support", "relay", "install")
+}
+
+// resolveShared puts the install in a machine-wide root and everything
+// else where the system layout would, so each user keeps their own data,
+// config and launcher.
+func resolveShared(os OS, configDir, cacheDir string, opts Options) (Paths, error) {
+	root := opts.InstallHint
+	if root == "" {
+		switch os {
+		case Linux, Darwin:
+			root = filepath.Join("/opt", "relay")
+		case Windows:
+			// Beside the system config file in %ProgramData%\relay
+			system := SystemConfigFile()
+			if system == "" {
+				return Paths{}, fmt.Errorf("shared layout requires %%ProgramData%% or an explicit install path")
+			}
+			root = filepath.Dir(system)
+		default:
+			return Paths{}, fmt.Errorf("unsupported OS: %s", os)
+		}
+	}
+
+	p, err := resolveSystem(os, configDir, cacheDir, Options{DataHint: opts.DataHint, BinHint: opts.BinHint})
+	if err != nil {
+		return Paths{}, err
+	}
+	p.InstallDir = root
+	p.Shared = true
+	return p, nil
+}
+
 func resolvePortable(configDir, cacheDir string, opts Options) (Paths, error) {
 	root := opts.InstallHint
-	if root == "" || root == "auto" {
+	if root == "" {
 		return Paths{}, fmt.Errorf("portable layout requires explicit install path")
 	}
 
-	root = filepath.Clean(root)
-
 	return Paths{
 		InstallDir: root,
-		DataDir:    filepath.Join(root, "data"),
-		BinDir:     filepath.Join(root, "bin"),
+		DataDir:    orDefault(opts.DataHint, filepath.Join(root, "data")),
+		BinDir:     orDefault(opts.BinHint, filepath.Join(root, "bin")),
 		ConfigDir:  filepath.Join(root, "config"),
 		CacheDir:   filepath.Join(root, "cache"),
+		StateDir:   filepath.Join(root, "state"),
 	}, nil
 }
+
+// named separates a named install from the others: the install directory
+// gets a subdirectory for the install unless hinted, and so do the data,
+// bin, cache and state directories unless hinted or inside the install
+// directory, where they move with it. Removing one install then leaves the
+// others alone.
+func (p Paths) named(opts Options) Paths {
+	install := p.InstallDir
+	if opts.InstallHint == "" {
+		install = filepath.Join(p.InstallDir, opts.Name)
+	}
+
+	dirs := []struct {
+		dir  *string
+		hint string
+	}{
+		{&p.DataDir, opts.DataHint},
+		{&p.BinDir, opts.BinHint},
+		{&p.CacheDir, ""},
+		{&p.StateDir, ""},
+	}
+	for _, d := range dirs {
+		switch {
+		case d.hint != "":
+		case Within(*d.dir, p.InstallDir):
+			rel, _ := filepath.Rel(p.InstallDir, *d.dir)
+			*d.dir = filepath.Join(install, rel)
+		default:
+			*d.dir = filepath.Join(*d.dir, opts.Name)
+		}
+	}
+
+	p.InstallDir = install
+	return p
+}
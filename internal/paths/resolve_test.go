@@ -1,7 +1,9 @@
 package paths
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -32,6 +34,9 @@ func TestResolvePortable(t *testing.T) {
 	if p.CacheDir != filepath.Join(root, "cache") {
 		t.Errorf("CacheDir = %v, want %v", p.CacheDir, filepath.Join(root, "cache"))
 	}
+	if p.BackupDir != root+"-backups" {
+		t.Errorf("BackupDir = %v, want %v", p.BackupDir, root+"-backups")
+	}
 }
 
 func TestResolvePortableRequiresInstallHint(t *testing.T) {
@@ -73,14 +78,15 @@ func TestPathsOwned(t *testing.T) {
 		BinDir:     "/usr/bin",
 		ConfigDir:  "/etc/relay",
 		CacheDir:   "/var/cache/relay",
+		StateDir:   "/var/lib/relay",
 	}
 
 	owned := p.Owned()
 
-	// Owned should include: InstallDir, DataDir, CacheDir
+	// Owned should include: InstallDir, DataDir, CacheDir, StateDir
 	// Owned should NOT include: ConfigDir (user config) or BinDir (shared system dir)
-	if len(owned) != 3 {
-		t.Fatalf("Owned() returned %d paths, want 3", len(owned))
+	if len(owned) != 4 {
+		t.Fatalf("Owned() returned %d paths, want 4", len(owned))
 	}
 
 	contains := func(slice []string, item string) bool {
@@ -98,6 +104,9 @@ func TestPathsOwned(t *testing.T) {
 	if !contains(owned, p.DataDir) {
 		t.Error("Owned() should include DataDir")
 	}
+	if !contains(owned, p.StateDir) {
+		t.Error("Owned() should include StateDir")
+	}
 	if !contains(owned, p.CacheDir) {
 		t.Error("Owned() should include CacheDir")
 	}
@@ -120,3 +129,485 @@ func TestCurrentOS(t *testing.T) {
 		t.Errorf("CurrentOS() = %v, want linux, darwin, or windows", os)
 	}
 }
+
+func TestResolveHints(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SECURE_VOL", "/mnt/secure")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	tests := []struct {
+		name        string
+		opts        Options
+		wantInstall string
+		wantData    string
+		wantBin     string
+	}{
+		{
+			name:        "system data and bin hints",
+			opts:        Options{Layout: SystemLayout, DataHint: "$SECURE_VOL/burp", BinHint: "~/bin"},
+			wantInstall: filepath.Join(home, ".local", "share", "relay"),
+			wantData:    "/mnt/secure/burp",
+			wantBin:     filepath.Join(home, "bin"),
+		},
+		{
+			name:        "system install hint moves data and bin",
+			opts:        Options{Layout: SystemLayout, InstallHint: "/opt/relay", DataHint: "auto"},
+			wantInstall: "/opt/relay",
+			wantData:    "/opt/relay/data",
+			wantBin:     "/opt/relay/bin",
+		},
+		{
+			name:        "portable data and bin hints",
+			opts:        Options{Layout: PortableLayout, InstallHint: "~/burp", DataHint: "${SECURE_VOL}/burp-data", BinHint: "~/bin"},
+			wantInstall: filepath.Join(home, "burp"),
+			wantData:    "/mnt/secure/burp-data",
+			wantBin:     filepath.Join(home, "bin"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if CurrentOS() != Linux {
+				t.Skip("expected defaults are Linux paths")
+			}
+
+			p, err := Resolve(tt.opts)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			if p.InstallDir != tt.wantInstall {
+				t.Errorf("InstallDir = %v, want %v", p.InstallDir, tt.wantInstall)
+			}
+			if p.DataDir != tt.wantData {
+				t.Errorf("DataDir = %v, want %v", p.DataDir, tt.wantData)
+			}
+			if p.BinDir != tt.wantBin {
+				t.Errorf("BinDir = %v, want %v", p.BinDir, tt.wantBin)
+			}
+		})
+	}
+}
+
+func TestResolveXDG(t *testing.T) {
+	if CurrentOS() != Linux {
+		t.Skip("XDG variables only apply on Linux")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Paths
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{},
+			want: Paths{
+				InstallDir: filepath.Join(home, ".local", "share", "relay"),
+				ConfigDir:  filepath.Join(home, ".config", "relay"),
+				CacheDir:   filepath.Join(home, ".cache", "relay"),
+				StateDir:   filepath.Join(home, ".local", "state", "relay"),
+			},
+		},
+		{
+			name: "XDG variables",
+			env: map[string]string{
+				"XDG_DATA_HOME":   "/xdg/data",
+				"XDG_CONFIG_HOME": "/xdg/config",
+				"XDG_CACHE_HOME":  "/xdg/cache",
+				"XDG_STATE_HOME":  "/xdg/state",
+			},
+			want: Paths{
+				InstallDir: "/xdg/data/relay",
+				ConfigDir:  "/xdg/config/relay",
+				CacheDir:   "/xdg/cache/relay",
+				StateDir:   "/xdg/state/relay",
+			},
+		},
+		{
+			name: "relative values are ignored",
+			env:  map[string]string{"XDG_DATA_HOME": "data", "XDG_STATE_HOME": "state"},
+			want: Paths{
+				InstallDir: filepath.Join(home, ".local", "share", "relay"),
+				ConfigDir:  filepath.Join(home, ".config", "relay"),
+				CacheDir:   filepath.Join(home, ".cache", "relay"),
+				StateDir:   filepath.Join(home, ".local", "state", "relay"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			p, err := Resolve(Options{Layout: SystemLayout})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			if p.InstallDir != tt.want.InstallDir {
+				t.Errorf("InstallDir = %v, want %v", p.InstallDir, tt.want.InstallDir)
+			}
+			if p.ConfigDir != tt.want.ConfigDir {
+				t.Errorf("ConfigDir = %v, want %v", p.ConfigDir, tt.want.ConfigDir)
+			}
+			if p.CacheDir != tt.want.CacheDir {
+				t.Errorf("CacheDir = %v, want %v", p.CacheDir, tt.want.CacheDir)
+			}
+			if p.StateDir != tt.want.StateDir {
+				t.Errorf("StateDir = %v, want %v", p.StateDir, tt.want.StateDir)
+			}
+		})
+	}
+}
+
+func TestResolveShared(t *testing.T) {
+	if CurrentOS() != Linux {
+		t.Skip("expected defaults are Linux paths")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	tests := []struct {
+		name        string
+		opts        Options
+		wantInstall string
+		wantData    string
+		wantBin     string
+	}{
+		{
+			name:        "default root",
+			opts:        Options{Layout: SharedLayout},
+			wantInstall: "/opt/relay",
+			wantData:    filepath.Join(home, ".local", "share", "relay", "data"),
+			wantBin:     filepath.Join(home, ".local", "share", "relay", "bin"),
+		},
+		{
+			name:        "configured root keeps per-user data",
+			opts:        Options{Layout: SharedLayout, InstallHint: "/srv/tools/relay", BinHint: "~/bin"},
+			wantInstall: "/srv/tools/relay",
+			wantData:    filepath.Join(home, ".local", "share", "relay", "data"),
+			wantBin:     filepath.Join(home, "bin"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Resolve(tt.opts)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			if !p.Shared {
+				t.Error("Shared = false, want true")
+			}
+			if p.InstallDir != tt.wantInstall {
+				t.Errorf("InstallDir = %v, want %v", p.InstallDir, tt.wantInstall)
+			}
+			if p.DataDir != tt.wantData {
+				t.Errorf("DataDir = %v, want %v", p.DataDir, tt.wantData)
+			}
+			if p.BinDir != tt.wantBin {
+				t.Errorf("BinDir = %v, want %v", p.BinDir, tt.wantBin)
+			}
+			for _, dir := range p.Owned() {
+				if dir == p.InstallDir {
+					t.Errorf("Owned() includes shared install %s", dir)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveNamed(t *testing.T) {
+	if CurrentOS() != Linux {
+		t.Skip("expected defaults are Linux paths")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	share := filepath.Join(home, ".local", "share", "relay")
+	state := filepath.Join(home, ".local", "state", "relay")
+	cache := filepath.Join(home, ".cache", "relay")
+
+	tests := []struct {
+		name        string
+		opts        Options
+		wantInstall string
+		wantData    string
+		wantBin     string
+		wantCache   string
+		wantState   string
+	}{
+		{
+			name:        "system defaults",
+			opts:        Options{Layout: SystemLayout, Name: "pro"},
+			wantInstall: filepath.Join(share, "pro"),
+			wantData:    filepath.Join(share, "pro", "data"),
+			wantBin:     filepath.Join(share, "pro", "bin"),
+			wantCache:   filepath.Join(cache, "pro"),
+			wantState:   filepath.Join(state, "pro"),
+		},
+		{
+			name:        "hints are kept",
+			opts:        Options{Layout: SystemLayout, Name: "pro", InstallHint: "/srv/burp-pro", BinHint: "~/bin"},
+			wantInstall: "/srv/burp-pro",
+			wantData:    "/srv/burp-pro/data",
+			wantBin:     filepath.Join(home, "bin"),
+			wantCache:   filepath.Join(cache, "pro"),
+			wantState:   filepath.Join(state, "pro"),
+		},
+		{
+			name:        "shared root",
+			opts:        Options{Layout: SharedLayout, Name: "community"},
+			wantInstall: "/opt/relay/community",
+			wantData:    filepath.Join(share, "data", "community"),
+			wantBin:     filepath.Join(share, "bin", "community"),
+			wantCache:   filepath.Join(cache, "community"),
+			wantState:   filepath.Join(state, "community"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Resolve(tt.opts)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			got := []string{p.InstallDir, p.DataDir, p.BinDir, p.CacheDir, p.StateDir}
+			want := []string{tt.wantInstall, tt.wantData, tt.wantBin, tt.wantCache, tt.wantState}
+			for i, name := range []string{"InstallDir", "DataDir", "BinDir", "CacheDir", "StateDir"} {
+				if got[i] != want[i] {
+					t.Errorf("%s = %v, want %v", name, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindLegacy(t *testing.T) {
+	if CurrentOS() != Linux {
+		t.Skip("legacy locations are Linux paths")
+	}
+
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(xdg, "data"))
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(xdg, "cache"))
+	t.Setenv("XDG_STATE_HOME", "")
+
+	legacyInstall := filepath.Join(home, ".local", "share", "relay")
+	legacyCache := filepath.Join(home, ".cache", "relay")
+	for _, dir := range []string{legacyInstall, legacyCache} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A populated new cache location must not be overwritten
+	if err := os.MkdirAll(filepath.Join(xdg, "cache", "relay", "jdk"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{Layout: SystemLayout}
+	p, err := Resolve(opts)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	found, err := FindLegacy(p, opts)
+	if err != nil {
+		t.Fatalf("FindLegacy() error = %v", err)
+	}
+	if len(found) != 1 || found[0].Name != "install" {
+		t.Fatalf("FindLegacy() = %+v, want only install", found)
+	}
+	if found[0].From != legacyInstall || found[0].To != p.InstallDir {
+		t.Errorf("FindLegacy() = %+v", found[0])
+	}
+
+	// An install hint pins the install location
+	hinted := Options{Layout: SystemLayout, InstallHint: "/opt/relay"}
+	if found, _ := FindLegacy(p, hinted); len(found) != 0 {
+		t.Errorf("FindLegacy() with install hint = %+v, want none", found)
+	}
+
+	if err := Relocate(found[0]); err != nil {
+		t.Fatalf("Relocate() error = %v", err)
+	}
+	if _, err := os.Stat(p.InstallDir); err != nil {
+		t.Errorf("install not moved: %v", err)
+	}
+	if _, err := os.Stat(legacyInstall); !os.IsNotExist(err) {
+		t.Errorf("legacy install still present: %v", err)
+	}
+}
+
+func TestResolveRejectsDangerousHints(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{
+			name:    "relative path",
+			opts:    Options{Layout: SystemLayout, DataHint: "burp-data"},
+			wantErr: "data path must be absolute",
+		},
+		{
+			name:    "unset variable",
+			opts:    Options{Layout: SystemLayout, DataHint: "$RELAY_TEST_UNSET/data"},
+			wantErr: "RELAY_TEST_UNSET is not set",
+		},
+		{
+			name:    "data is home",
+			opts:    Options{Layout: SystemLayout, DataHint: "~"},
+			wantErr: "contains your home directory",
+		},
+		{
+			name:    "install is root",
+			opts:    Options{Layout: PortableLayout, InstallHint: "/"},
+			wantErr: "is a filesystem root",
+		},
+		{
+			name:    "shared bin inside data",
+			opts:    Options{Layout: SystemLayout, DataHint: "/srv/burp", BinHint: "/srv/burp/bin"},
+			wantErr: "bin directory /srv/burp/bin is inside the data directory",
+		},
+		{
+			name:    "config inside install",
+			opts:    Options{Layout: SystemLayout, InstallHint: "~/.config"},
+			wantErr: "config directory",
+		},
+		{
+			name:    "install is a system directory",
+			opts:    Options{Layout: PortableLayout, InstallHint: "/usr/local"},
+			wantErr: "protected system or home directory",
+		},
+		{
+			name:    "data is a home directory",
+			opts:    Options{Layout: SystemLayout, DataHint: "~/Documents"},
+			wantErr: "protected system or home directory",
+		},
+		{
+			name:    "backup dir inside data",
+			opts:    Options{Layout: SystemLayout, DataHint: "/srv/burp", BackupHint: "/srv/burp/backups"},
+			wantErr: "backup directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if CurrentOS() != Linux {
+				t.Skip("paths are Linux paths")
+			}
+
+			_, err := Resolve(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// asDarwin resolves paths as on macOS for the rest of the test.
+func asDarwin(t *testing.T) string {
+	t.Helper()
+	if filepath.Separator != '/' {
+		t.Skip("macOS paths need / separators")
+	}
+	prev := goos
+	goos = "darwin"
+	t.Cleanup(func() { goos = prev })
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return home
+}
+
+func TestResolveDarwinSystem(t *testing.T) {
+	home := asDarwin(t)
+	support := filepath.Join(home, "Library", "Application Support", "relay")
+
+	p, err := Resolve(Options{Layout: SystemLayout})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := Paths{
+		InstallDir: filepath.Join(support, "install"),
+		DataDir:    filepath.Join(support, "install", "data"),
+		BinDir:     filepath.Join(support, "install", "bin"),
+		ConfigDir:  support,
+		CacheDir:   filepath.Join(home, "Library", "Caches", "relay"),
+		StateDir:   filepath.Join(support, "install", "state"),
+		BackupDir:  filepath.Join(support, "backups"),
+		Layout:     SystemLayout,
+	}
+	if p != want {
+		t.Errorf("Resolve() = %+v, want %+v", p, want)
+	}
+}
+
+func TestFindLegacyDarwin(t *testing.T) {
+	home := asDarwin(t)
+	support := filepath.Join(home, "Library", "Application Support", "relay")
+	for _, name := range []string{OwnershipMarker, ".relay-version", "burpsuite.jar", ConfigFileName, "bin/burpsuite", "backups/a.tar.gz"} {
+		path := filepath.Join(support, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{Layout: SystemLayout}
+	p, err := Resolve(opts)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	found, err := FindLegacy(p, opts)
+	if err != nil {
+		t.Fatalf("FindLegacy() error = %v", err)
+	}
+	if len(found) != 1 || found[0].From != support || found[0].To != p.InstallDir {
+		t.Fatalf("FindLegacy() = %+v, want the install in %s", found, support)
+	}
+
+	if err := Relocate(found[0]); err != nil {
+		t.Fatalf("Relocate() error = %v", err)
+	}
+	for _, name := range []string{OwnershipMarker, ".relay-version", "burpsuite.jar", "bin/burpsuite"} {
+		if _, err := os.Stat(filepath.Join(p.InstallDir, name)); err != nil {
+			t.Errorf("%s not moved: %v", name, err)
+		}
+	}
+	for _, name := range []string{ConfigFileName, "backups/a.tar.gz"} {
+		if _, err := os.Stat(filepath.Join(support, name)); err != nil {
+			t.Errorf("%s moved: %v", name, err)
+		}
+	}
+
+	// Once moved there is nothing left to find
+	if found, _ := FindLegacy(p, opts); len(found) != 0 {
+		t.Errorf("FindLegacy() after Relocate = %+v, want none", found)
+	}
+}
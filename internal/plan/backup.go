@@ -0,0 +1,56 @@
+package plan
+
+import "time"
+
+// BackupSource is one part of the user state kept in a backup.
+type BackupSource struct {
+	Name string // Component name, also its top-level entry in the archive
+	Path string // File or directory on this machine
+}
+
+// BackupManifest describes the contents of a backup archive.
+type BackupManifest struct {
+	Format     int               `json:"format"`
+	Created    time.Time         `json:"created"`
+	Product    string            `json:"product"`
+	Edition    string            `json:"edition"`
+	Components []BackupComponent `json:"components"`
+}
+
+// BackupComponent is one part of the state in a backup.
+type BackupComponent struct {
+	Name string `json:"name"`
+	Path string `json:"path"` // Location on the machine that made the backup
+	Dir  bool   `json:"dir"`
+}
+
+// BackupPlan is an immutable plan for archiving Burp and relay user state.
+type BackupPlan struct {
+	Product string
+	Edition string
+	Archive string // Archive file to write
+	Sources []BackupSource
+}
+
+func (p BackupPlan) Kind() Kind {
+	return Backup
+}
+
+// RestorePlan is an immutable plan for restoring a backup archive.
+// Components are restored to Targets, matched by name, so an archive
+// made on another machine or layout lands in this one's locations.
+type RestorePlan struct {
+	Product string
+	Archive string
+	Targets []BackupSource
+	Paths   Paths
+
+	// Set when the archive was already extracted to Extracted and
+	// validated, so it isn't read again; nil to read it when executing
+	Manifest  *BackupManifest
+	Extracted string
+}
+
+func (p RestorePlan) Kind() Kind {
+	return Restore
+}
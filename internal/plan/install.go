@@ -4,15 +4,19 @@ import "github.com/sdmrf/relay/pkg/config"
 
 // Artifact represents a downloadable artifact.
 type Artifact struct {
-	Name   string // Display name (e.g., "burpsuite.jar")
-	URL    string // Download URL
-	Target string // Target file path
+	Name      string // Display name (e.g., "burpsuite.jar")
+	URL       string // Download URL
+	Target    string // Target file path
+	ExtractTo string // Extract the downloaded archive here and delete it; empty keeps the file
+	Space     uint64 // Disk space the download needs, with headroom; 0 if unknown
 }
 
 // JREArtifact represents a JRE to download and extract.
 type JREArtifact struct {
-	Name      string // Display name (e.g., "Eclipse Temurin JRE 21.0.5")
+	Name      string // Display name (e.g., "Eclipse Temurin JRE 21.0.5+11")
+	Version   string // Release version recorded in the JRE marker
 	URL       string // Download URL
+	Checksum  string // Expected SHA-256 of the archive (optional)
 	Target    string // Download target path (.tar.gz or .zip)
 	ExtractTo string // Extraction destination directory
 }
@@ -27,8 +31,10 @@ type InstallPlan struct {
 	JavaMin     int
 	JVMArgs     []string
 	Layout      config.LayoutMode
+	Group       string // Group given access to a shared install
 	Artifact    Artifact
 	JREArtifact *JREArtifact // Optional: nil if JRE not needed
+	Replaces    string       // Earlier release directory to delete once installed; empty if none
 }
 
 func (p InstallPlan) Kind() Kind {
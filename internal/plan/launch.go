@@ -8,9 +8,13 @@ type LaunchPlan struct {
 	Product      string
 	Version      string
 	Paths        Paths
+	Jar          string // JAR to run; empty means burpsuite.jar in the install dir
 	JVMArgs      []string
+	Heap         string // Max heap expression, expanded at launch time
 	JavaMin      int
-	JavaStrategy config.JavaStrategy // How to resolve Java (auto/system/bundled)
+	JavaStrategy config.JavaStrategy // How to resolve Java (auto/system/bundled/path/managed)
+	JavaHome     string              // Java home for the path strategy
+	JavaPin      string              // Pinned JRE release for the managed strategy
 }
 
 func (p LaunchPlan) Kind() Kind {
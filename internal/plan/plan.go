@@ -6,10 +6,18 @@ import "github.com/sdmrf/relay/internal/paths"
 type Kind string
 
 const (
-	Install Kind = "install"
-	Update  Kind = "update"
-	Launch  Kind = "launch"
-	Remove  Kind = "remove"
+	Install  Kind = "install"
+	Update   Kind = "update"
+	Launch   Kind = "launch"
+	Remove   Kind = "remove"
+	Runtime  Kind = "runtime"
+	Backup   Kind = "backup"
+	Restore  Kind = "restore"
+	Repair   Kind = "repair"
+	Sync     Kind = "sync"
+	Shortcut Kind = "shortcut"
+
+	SupportBundle Kind = "support-bundle"
 )
 
 // Plan is implemented by all plan types.
@@ -24,6 +32,10 @@ type Paths struct {
 	BinDir     string
 	ConfigDir  string
 	CacheDir   string
+	StateDir   string
+	Shared     bool // InstallDir is machine-wide; see paths.Paths
+	Layout     paths.Layout
+	BackupDir  string
 }
 
 // FromResolved converts paths.Paths to plan.Paths.
@@ -34,15 +46,24 @@ func FromResolved(p paths.Paths) Paths {
 		BinDir:     p.BinDir,
 		ConfigDir:  p.ConfigDir,
 		CacheDir:   p.CacheDir,
+		StateDir:   p.StateDir,
+		Shared:     p.Shared,
+		Layout:     p.Layout,
+		BackupDir:  p.BackupDir,
 	}
 }
 
 // Owned returns paths that relay owns and can safely modify/delete.
-// Excludes ConfigDir to preserve user configuration on uninstall.
+// Excludes ConfigDir to preserve user configuration on uninstall, and a
+// shared InstallDir that other users still need.
 func (p Paths) Owned() []string {
+	if p.Shared {
+		return []string{p.DataDir, p.CacheDir, p.StateDir}
+	}
 	return []string{
 		p.InstallDir,
 		p.DataDir,
 		p.CacheDir,
+		p.StateDir,
 	}
 }
@@ -1,6 +1,7 @@
 package plan
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sdmrf/relay/pkg/config"
@@ -46,13 +47,14 @@ func TestPathsOwned(t *testing.T) {
 		BinDir:     "/usr/bin",
 		ConfigDir:  "/etc/relay",
 		CacheDir:   "/var/cache/relay",
+		StateDir:   "/var/lib/relay",
 	}
 
 	owned := p.Owned()
 
-	// Should contain InstallDir, DataDir, CacheDir
+	// Should contain InstallDir, DataDir, CacheDir, StateDir
 	// Should NOT contain ConfigDir or BinDir
-	expected := []string{"/opt/relay", "/var/relay", "/var/cache/relay"}
+	expected := []string{"/opt/relay", "/var/relay", "/var/cache/relay", "/var/lib/relay"}
 
 	if len(owned) != len(expected) {
 		t.Fatalf("Paths.Owned() returned %d paths, want %d", len(owned), len(expected))
@@ -65,6 +67,33 @@ func TestPathsOwned(t *testing.T) {
 	}
 }
 
+func TestRemovePlanTargets(t *testing.T) {
+	p := Paths{
+		InstallDir: "/opt/relay",
+		DataDir:    "/opt/relay/data",
+		CacheDir:   "/var/cache/relay",
+		StateDir:   "/var/lib/relay",
+	}
+
+	tests := []struct {
+		name  string
+		purge bool
+		want  []string
+	}{
+		{"keep data", false, []string{"/opt/relay", "/var/cache/relay", "/var/lib/relay"}},
+		{"purge", true, []string{"/opt/relay", "/opt/relay/data", "/var/cache/relay", "/var/lib/relay"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RemovePlan{Paths: p, Purge: tt.purge}.Targets()
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Targets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPathsOwnedExcludesConfig(t *testing.T) {
 	p := Paths{
 		InstallDir: "/opt/relay",
@@ -145,3 +174,11 @@ func TestRemovePlanImmutable(t *testing.T) {
 		t.Errorf("Paths.InstallDir = %v, want /opt/relay", p.Paths.InstallDir)
 	}
 }
+
+func TestSyncPlanKind(t *testing.T) {
+	p := SyncPlan{Steps: []SyncStep{{Change: SyncAdd, Plan: InstallPlan{Product: "burpsuite"}}}}
+
+	if got := p.Kind(); got != Sync {
+		t.Errorf("SyncPlan.Kind() = %v, want %v", got, Sync)
+	}
+}
@@ -5,8 +5,24 @@ package plan
 type RemovePlan struct {
 	Product string
 	Paths   Paths
+	Purge   bool // Also delete DataDir, which is kept by default
+
+	Shortcut *ShortcutPlan // Desktop shortcut to delete too; nil when there is none
 }
 
 func (p RemovePlan) Kind() Kind {
 	return Remove
 }
+
+// Targets returns the owned directories the plan deletes. Without Purge
+// DataDir is left out, and kept even when it sits inside another target.
+func (p RemovePlan) Targets() []string {
+	var targets []string
+	for _, dir := range p.Paths.Owned() {
+		if dir == p.Paths.DataDir && !p.Purge {
+			continue
+		}
+		targets = append(targets, dir)
+	}
+	return targets
+}
@@ -0,0 +1,18 @@
+package plan
+
+// RepairPlan is an immutable plan for fixing problems found by relay
+// doctor. Empty fields leave that part of the installation alone.
+type RepairPlan struct {
+	Product     string
+	Paths       Paths
+	Group       string       // Group given access to a shared install
+	Dirs        []string     // Missing directories to create
+	Writable    []string     // Directories to make writable by their owner
+	JREArtifact *JREArtifact // Bundled JRE to download
+	Artifact    *Artifact    // Product JAR to download again
+	Launch      *LaunchPlan  // Plan whose launcher is regenerated
+}
+
+func (p RepairPlan) Kind() Kind {
+	return Repair
+}
@@ -0,0 +1,23 @@
+package plan
+
+// RuntimeAction is the change a RuntimePlan applies to the bundled JRE.
+type RuntimeAction string
+
+const (
+	RuntimeInstall RuntimeAction = "install" // Download and extract JREArtifact, replacing any existing JRE
+	RuntimeRemove  RuntimeAction = "remove"  // Delete the bundled JRE and its marker
+)
+
+// RuntimePlan is an immutable plan for changing the bundled JRE
+// independently of the product.
+type RuntimePlan struct {
+	Action         RuntimeAction
+	CurrentVersion string // Installed JRE release; empty if none
+	TargetVersion  string // Release after the plan is applied; empty on remove
+	Paths          Paths
+	JREArtifact    *JREArtifact // Required for RuntimeInstall
+}
+
+func (p RuntimePlan) Kind() Kind {
+	return Runtime
+}
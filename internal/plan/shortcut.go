@@ -0,0 +1,13 @@
+package plan
+
+// ShortcutPlan adds or removes the desktop shortcut for an install's
+// launcher.
+type ShortcutPlan struct {
+	Name     string // Shown in the application menu; also names the shortcut file
+	Launcher string // Launcher the shortcut runs
+	Remove   bool
+}
+
+func (p ShortcutPlan) Kind() Kind {
+	return Shortcut
+}
@@ -0,0 +1,22 @@
+package plan
+
+// BundleFile is one entry in a support bundle.
+type BundleFile struct {
+	Name string // Path inside the archive
+	Path string // File to include; unused when Data is set
+	Data []byte // Generated content, such as the doctor report
+	Tail int64  // Keep only the last Tail bytes of Path; 0 keeps it all
+}
+
+// SupportBundlePlan is an immutable plan for writing a redacted archive
+// of diagnostics to attach to a bug report.
+type SupportBundlePlan struct {
+	Archive string       // Zip file to write
+	Files   []BundleFile // Entries; missing Paths are left out
+	Redact  []string     // Regular expressions removed from every entry, besides the built-in ones
+	Home    string       // Home directory, replaced with ~ in every entry
+}
+
+func (p SupportBundlePlan) Kind() Kind {
+	return SupportBundle
+}
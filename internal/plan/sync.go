@@ -0,0 +1,29 @@
+package plan
+
+// SyncChange is how a sync step changes the machine.
+type SyncChange string
+
+const (
+	SyncAdd    SyncChange = "+" // Something declared is missing
+	SyncModify SyncChange = "~" // Something present differs from the config
+	SyncDelete SyncChange = "-" // Something present is no longer declared
+)
+
+// SyncStep is one change of a SyncPlan, applied by executing Plan.
+type SyncStep struct {
+	Install string // Named install the step belongs to; empty without installs
+	Change  SyncChange
+	Summary string // One line for the diff, e.g. "burpsuite 2024.1.1 -> 2024.2.1"
+	Plan    Plan
+}
+
+// SyncPlan is an immutable plan that converges the machine onto the
+// config. It holds only the steps needed, in order; a step that fails
+// skips the remaining steps of its install.
+type SyncPlan struct {
+	Steps []SyncStep
+}
+
+func (p SyncPlan) Kind() Kind {
+	return Sync
+}
@@ -7,7 +7,9 @@ type UpdatePlan struct {
 	CurrentVersion string
 	TargetVersion  string
 	Paths          Paths
+	Group          string // Group given access to a shared install
 	Artifact       Artifact
+	Replaces       string // Release directory the update supersedes; empty if it is overwritten in place
 }
 
 func (p UpdatePlan) Kind() Kind {
@@ -3,14 +3,20 @@ package burpsuite
 import (
 	"path/filepath"
 
-	"github.com/sdmrf/relay/internal/downloader"
+	"github.com/sdmrf/relay/internal/plan"
 )
 
+// jarSpace is the space downloading the JAR needs, with headroom. The
+// Professional JAR is about 600 MB and an update downloads it next to the
+// old one.
+const jarSpace = 800 << 20
+
 // ArtifactJar returns the download artifact for the Burp Suite JAR.
-func (b *BurpSuite) ArtifactJar() downloader.Artifact {
-	return downloader.Artifact{
-		Name:   "burpsuite.jar",
+func (b *BurpSuite) ArtifactJar() plan.Artifact {
+	return plan.Artifact{
+		Name:   JarName,
 		URL:    burpDownloadURL(b.cfg.Product.Edition),
-		Target: filepath.Join(b.paths.InstallDir, "burpsuite.jar"),
+		Target: filepath.Join(b.paths.InstallDir, JarName),
+		Space:  jarSpace,
 	}
 }
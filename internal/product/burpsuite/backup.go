@@ -0,0 +1,71 @@
+package burpsuite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+)
+
+// ResolveBackup plans an archive of Burp's user state, the relay user
+// config and the data directory, named after now.
+func (b *BurpSuite) ResolveBackup(now time.Time) (plan.BackupPlan, error) {
+	sources, err := b.stateSources()
+	if err != nil {
+		return plan.BackupPlan{}, err
+	}
+
+	name := fmt.Sprintf("relay-backup-%s.tar.gz", now.Format("20060102-150405"))
+	return plan.BackupPlan{
+		Product: b.Name(),
+		Edition: b.cfg.Product.Edition,
+		Archive: filepath.Join(b.paths.BackupDir, name),
+		Sources: sources,
+	}, nil
+}
+
+// ResolveRestore plans restoring archive to this machine's locations.
+func (b *BurpSuite) ResolveRestore(archive string) (plan.RestorePlan, error) {
+	targets, err := b.stateSources()
+	if err != nil {
+		return plan.RestorePlan{}, err
+	}
+
+	return plan.RestorePlan{
+		Product: b.Name(),
+		Archive: archive,
+		Targets: targets,
+		Paths:   plan.FromResolved(b.paths),
+	}, nil
+}
+
+// stateSources lists where Burp and relay keep user state. Burp stores
+// options, extension settings and the license in ~/.BurpSuite and in Java
+// preferences, which are files only on Linux: macOS keeps them in a plist
+// shared by every Java app, and Windows in the registry.
+func (b *BurpSuite) stateSources() ([]plan.BackupSource, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home directory: %w", err)
+	}
+
+	configFile, err := paths.UserConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := []plan.BackupSource{
+		{Name: "burp", Path: filepath.Join(home, ".BurpSuite")},
+	}
+	if paths.CurrentOS() == paths.Linux {
+		sources = append(sources, plan.BackupSource{Name: "java-prefs", Path: filepath.Join(home, ".java", ".userPrefs", "burp")})
+	}
+	sources = append(sources,
+		plan.BackupSource{Name: "config", Path: configFile},
+		plan.BackupSource{Name: "data", Path: b.paths.DataDir},
+	)
+	return sources, nil
+}
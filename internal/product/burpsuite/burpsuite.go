@@ -4,9 +4,16 @@ import (
 	"fmt"
 
 	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/product"
 	"github.com/sdmrf/relay/pkg/config"
 )
 
+func init() {
+	product.Register("burpsuite", func(cfg config.Config, p paths.Paths) (product.Product, error) {
+		return New(cfg, p)
+	})
+}
+
 // BurpSuite encapsulates all Burp-specific resolution logic.
 // No filesystem access, no network access - pure data transformation.
 type BurpSuite struct {
@@ -0,0 +1,43 @@
+package burpsuite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sdmrf/relay/internal/diagnostics"
+)
+
+// Burp Suite checks for relay doctor.
+func init() {
+	diagnostics.Register(diagnostics.Definition{
+		ID:       "proxy-port",
+		Name:     "Proxy listener",
+		Category: diagnostics.CategoryNetwork,
+		Run: func(ctx context.Context, env diagnostics.Env) []diagnostics.Check {
+			b, ok := env.Product.(*BurpSuite)
+			if !ok {
+				return nil
+			}
+			pid := runningPID(env.Paths.StateDir)
+
+			var checks []diagnostics.Check
+			for _, addr := range b.ProxyListeners() {
+				checks = append(checks, diagnostics.CheckProxyListener(addr, pid))
+			}
+			return checks
+		},
+	})
+}
+
+// runningPID returns the PID the launcher recorded for Burp, or 0.
+func runningPID(stateDir string) int {
+	data, err := os.ReadFile(filepath.Join(stateDir, "burpsuite.pid"))
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
@@ -1,6 +1,10 @@
 package burpsuite
 
-import "github.com/sdmrf/relay/internal/plan"
+import (
+	"path/filepath"
+
+	"github.com/sdmrf/relay/internal/plan"
+)
 
 // ResolveLaunch creates an immutable LaunchPlan for Burp Suite.
 // Pure function - no filesystem or network access.
@@ -9,8 +13,12 @@ func (b *BurpSuite) ResolveLaunch() (plan.LaunchPlan, error) {
 		Product:      b.Name(),
 		Version:      b.cfg.Product.Version,
 		Paths:        plan.FromResolved(b.paths),
+		Jar:          filepath.Join(b.paths.InstallDir, JarName),
 		JVMArgs:      b.cfg.Runtime.Java.JVMArgs,
+		Heap:         b.cfg.Runtime.Java.Heap,
 		JavaMin:      b.cfg.Runtime.Java.MinVersion,
 		JavaStrategy: b.cfg.Runtime.Java.Strategy,
+		JavaHome:     b.cfg.Runtime.Java.JavaHome,
+		JavaPin:      b.cfg.Runtime.Java.Bundled.Version,
 	}, nil
 }
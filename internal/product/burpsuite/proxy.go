@@ -0,0 +1,133 @@
+package burpsuite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultProxyListener is where Burp's proxy listens out of the box.
+const defaultProxyListener = "127.0.0.1:8080"
+
+// ProxyListeners returns the addresses Burp's proxy listens on: the
+// burp.proxy_listeners setting, otherwise the running listeners in Burp's
+// saved user options, otherwise Burp's default.
+func (b *BurpSuite) ProxyListeners() []string {
+	if len(b.cfg.Burp.ProxyListeners) > 0 {
+		return b.cfg.Burp.ProxyListeners
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return []string{defaultProxyListener}
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".BurpSuite", userConfigFile(b.cfg.Product.Edition)))
+	if err != nil {
+		return []string{defaultProxyListener}
+	}
+
+	listeners, err := parseProxyListeners(data)
+	if err != nil || len(listeners) == 0 {
+		return []string{defaultProxyListener}
+	}
+	return listeners
+}
+
+// userConfigFile is the file in ~/.BurpSuite where an edition saves its
+// user options.
+func userConfigFile(edition string) string {
+	if edition == "community" {
+		return "UserConfigCommunity.json"
+	}
+	return "UserConfigPro.json"
+}
+
+// proxyListener is a listener entry in Burp's options JSON.
+type proxyListener struct {
+	ListenMode      string `json:"listen_mode"`
+	ListenerPort    int    `json:"listener_port"`
+	SpecificAddress string `json:"listen_specific_address"`
+	Running         *bool  `json:"running"`
+}
+
+// parseProxyListeners returns the addresses of running proxy listeners in
+// a Burp options file. Listeners appear under proxy.request_listeners in
+// user or project options depending on the Burp version, so every
+// request_listeners array in the document is read.
+func parseProxyListeners(data []byte) ([]string, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse Burp options: %w", err)
+	}
+
+	var addrs []string
+	seen := map[string]bool{}
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				child := v[key]
+				if key == "request_listeners" {
+					for _, addr := range listenerAddrs(child) {
+						if !seen[addr] {
+							seen[addr] = true
+							addrs = append(addrs, addr)
+						}
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	return addrs, nil
+}
+
+// listenerAddrs converts a request_listeners array to host:port addresses,
+// skipping listeners that are turned off.
+func listenerAddrs(v any) []string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var listeners []proxyListener
+	if err := json.Unmarshal(raw, &listeners); err != nil {
+		return nil
+	}
+
+	var addrs []string
+	for _, l := range listeners {
+		if l.ListenerPort == 0 || (l.Running != nil && !*l.Running) {
+			continue
+		}
+
+		host := "127.0.0.1"
+		switch strings.ToLower(l.ListenMode) {
+		case "all_interfaces":
+			host = "0.0.0.0"
+		case "specific_address":
+			if l.SpecificAddress != "" {
+				host = l.SpecificAddress
+			}
+		}
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(l.ListenerPort)))
+	}
+	return addrs
+}
@@ -0,0 +1,46 @@
+package burpsuite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProxyListeners(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "user options",
+			data: `{"user_options":{"proxy":{"request_listeners":[
+				{"listen_mode":"loopback_only","listener_port":8080,"running":true},
+				{"listen_mode":"all_interfaces","listener_port":8081,"running":true},
+				{"listen_mode":"specific_address","listen_specific_address":"192.168.56.1","listener_port":8082},
+				{"listen_mode":"loopback_only","listener_port":9090,"running":false}
+			]}}}`,
+			want: []string{"127.0.0.1:8080", "0.0.0.0:8081", "192.168.56.1:8082"},
+		},
+		{
+			name: "project options",
+			data: `{"project_options":{"proxy":{"request_listeners":[{"listener_port":8888}]}}}`,
+			want: []string{"127.0.0.1:8888"},
+		},
+		{
+			name: "no listeners",
+			data: `{"user_options":{"display":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProxyListeners([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("parseProxyListeners() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProxyListeners() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package burpsuite
+
+import (
+	"path/filepath"
+
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+)
+
+// logTail bounds how much of each log goes into a support bundle.
+const logTail = 1 << 20
+
+// logFiles are the logs the launchers write to the state directory.
+var logFiles = []string{"burpsuite.log", "burpsuite.err.log"}
+
+// SupportFiles lists Burp's files worth attaching to a bug report: the
+// installed version marker and the end of each launcher log. Files that
+// don't exist are left out of the bundle.
+func (b *BurpSuite) SupportFiles() []plan.BundleFile {
+	files := []plan.BundleFile{
+		{Name: "markers/" + product.VersionMarkerFile, Path: filepath.Join(b.paths.InstallDir, product.VersionMarkerFile)},
+	}
+	for _, name := range logFiles {
+		files = append(files, plan.BundleFile{Name: "logs/" + name, Path: filepath.Join(b.paths.StateDir, name), Tail: logTail})
+	}
+	return files
+}
@@ -0,0 +1,45 @@
+package product
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFile records in the install directory what was installed there,
+// as configured, so sync can tell when the config asks for something else.
+const ManifestFile = ".relay-manifest.json"
+
+// Manifest describes an install.
+type Manifest struct {
+	Product     string    `json:"product"`
+	Edition     string    `json:"edition,omitempty"`
+	Version     string    `json:"version"` // As configured; "latest" when unpinned
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// ReadManifest reads the manifest in installDir. Installs made before
+// manifests existed have none; the error then wraps os.ErrNotExist.
+func ReadManifest(installDir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(installDir, ManifestFile))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("parse %s: %w", ManifestFile, err)
+	}
+	return m, nil
+}
+
+// WriteManifest writes m to the manifest in installDir.
+func WriteManifest(installDir string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(installDir, ManifestFile), append(data, '\n'), 0o644)
+}
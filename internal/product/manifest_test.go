@@ -0,0 +1,33 @@
+package product
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := ReadManifest(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ReadManifest() on a pre-manifest install error = %v, want ErrNotExist", err)
+	}
+
+	want := Manifest{
+		Product:     "burpsuite",
+		Edition:     "community",
+		Version:     "latest",
+		InstalledAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := WriteManifest(dir, want); err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+	got, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("ReadManifest() = %+v, want %+v", got, want)
+	}
+}
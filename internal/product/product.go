@@ -1,12 +1,30 @@
 package product
 
-import "github.com/sdmrf/relay/internal/plan"
+import (
+	"time"
+
+	"github.com/sdmrf/relay/internal/plan"
+)
 
 // Product defines the contract for product-specific behavior.
 // Implementations provide resolution logic but no filesystem or network access.
 type Product interface {
 	Name() string
 	ResolveInstall() (plan.InstallPlan, error)
+	ResolveUpdate() (plan.UpdatePlan, error)
 	ResolveLaunch() (plan.LaunchPlan, error)
 	ResolveRemove() (plan.RemovePlan, error)
 }
+
+// Backuper is implemented by products whose user settings relay can back
+// up and restore.
+type Backuper interface {
+	ResolveBackup(now time.Time) (plan.BackupPlan, error)
+	ResolveRestore(archive string) (plan.RestorePlan, error)
+}
+
+// SupportFiler is implemented by products with files worth adding to a
+// support bundle, such as logs.
+type SupportFiler interface {
+	SupportFiles() []plan.BundleFile
+}
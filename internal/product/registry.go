@@ -0,0 +1,57 @@
+package product
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+// Factory creates a product for the config and resolved paths.
+type Factory func(cfg config.Config, p paths.Paths) (Product, error)
+
+var (
+	mu        sync.Mutex
+	factories = map[string]Factory{}
+)
+
+// Register makes a product available under the product.name it answers
+// to. Products register themselves from their package init. It panics on
+// a duplicate name, which is a programming error.
+func Register(name string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("product: %q registered twice", name))
+	}
+	factories[name] = f
+}
+
+// New creates the product named by cfg.Product.Name.
+func New(cfg config.Config, p paths.Paths) (Product, error) {
+	mu.Lock()
+	f, ok := factories[cfg.Product.Name]
+	mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown product %q (available: %s)", cfg.Product.Name, strings.Join(Names(), ", "))
+	}
+	return f(cfg, p)
+}
+
+// Names returns the registered product names, sorted.
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,31 @@
+package product
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+func TestNewUnknownProduct(t *testing.T) {
+	cfg := config.Default()
+	cfg.Product.Name = "nmap"
+
+	_, err := New(cfg, paths.Paths{})
+	if err == nil || !strings.Contains(err.Error(), `unknown product "nmap"`) {
+		t.Errorf("New() error = %v, want unknown product", err)
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	f := func(config.Config, paths.Paths) (Product, error) { return nil, nil }
+	Register("test-twice", f)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("second Register() did not panic")
+		}
+	}()
+	Register("test-twice", f)
+}
@@ -0,0 +1,65 @@
+package product
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VersionMarkerFile records the installed product version in the install
+// directory.
+const VersionMarkerFile = ".relay-version"
+
+// ReadVersionMarker reads the installed version from the marker file.
+func ReadVersionMarker(installDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(installDir, VersionMarkerFile))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WriteVersionMarker writes the version to the marker file.
+func WriteVersionMarker(installDir, version string) error {
+	markerPath := filepath.Join(installDir, VersionMarkerFile)
+	return os.WriteFile(markerPath, []byte(version+"\n"), 0o644)
+}
+
+// CompareVersions compares two version strings.
+// Returns:
+//
+//	-1 if a < b
+//	 0 if a == b
+//	 1 if a > b
+//
+// Handles version formats like "2024.1.1", "2023.12.1.4"
+func CompareVersions(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	maxLen := len(partsA)
+	if len(partsB) > maxLen {
+		maxLen = len(partsB)
+	}
+
+	for i := 0; i < maxLen; i++ {
+		var numA, numB int
+		if i < len(partsA) {
+			fmt.Sscanf(partsA[i], "%d", &numA)
+		}
+		if i < len(partsB) {
+			fmt.Sscanf(partsB[i], "%d", &numB)
+		}
+
+		if numA < numB {
+			return -1
+		}
+		if numA > numB {
+			return 1
+		}
+	}
+
+	return 0
+}
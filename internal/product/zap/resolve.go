@@ -0,0 +1,75 @@
+package zap
+
+import (
+	"fmt"
+
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+)
+
+// ResolveInstall creates an immutable InstallPlan for ZAP.
+// Pure function - no filesystem or network access.
+func (z *ZAP) ResolveInstall() (plan.InstallPlan, error) {
+	return plan.InstallPlan{
+		Product:  z.Name(),
+		Edition:  z.cfg.Product.Edition,
+		Version:  z.cfg.Product.Version,
+		Paths:    plan.FromResolved(z.paths),
+		JavaMin:  z.cfg.Runtime.Java.MinVersion,
+		JVMArgs:  z.cfg.Runtime.Java.JVMArgs,
+		Layout:   z.cfg.Layout.Mode,
+		Group:    z.cfg.Layout.Group,
+		Artifact: z.artifact(),
+	}, nil
+}
+
+// ResolveUpdate creates an immutable UpdatePlan for ZAP. The new release
+// unpacks next to the installed one, which it replaces.
+func (z *ZAP) ResolveUpdate() (plan.UpdatePlan, error) {
+	currentVersion, err := product.ReadVersionMarker(z.paths.InstallDir)
+	if err != nil {
+		return plan.UpdatePlan{}, fmt.Errorf("no installed version found: %w", err)
+	}
+
+	var replaces string
+	if currentVersion != z.cfg.Product.Version && releaseVersion.MatchString(currentVersion) {
+		replaces = z.releaseDir(currentVersion)
+	}
+
+	return plan.UpdatePlan{
+		Product:        z.Name(),
+		Edition:        z.cfg.Product.Edition,
+		CurrentVersion: currentVersion,
+		TargetVersion:  z.cfg.Product.Version,
+		Paths:          plan.FromResolved(z.paths),
+		Group:          z.cfg.Layout.Group,
+		Artifact:       z.artifact(),
+		Replaces:       replaces,
+	}, nil
+}
+
+// ResolveLaunch creates an immutable LaunchPlan for ZAP.
+// Pure function - no filesystem or network access.
+func (z *ZAP) ResolveLaunch() (plan.LaunchPlan, error) {
+	return plan.LaunchPlan{
+		Product:      z.Name(),
+		Version:      z.cfg.Product.Version,
+		Paths:        plan.FromResolved(z.paths),
+		Jar:          z.jar(),
+		JVMArgs:      z.cfg.Runtime.Java.JVMArgs,
+		Heap:         z.cfg.Runtime.Java.Heap,
+		JavaMin:      z.cfg.Runtime.Java.MinVersion,
+		JavaStrategy: z.cfg.Runtime.Java.Strategy,
+		JavaHome:     z.cfg.Runtime.Java.JavaHome,
+		JavaPin:      z.cfg.Runtime.Java.Bundled.Version,
+	}, nil
+}
+
+// ResolveRemove creates an immutable RemovePlan for ZAP.
+// Pure function - no filesystem or network access.
+func (z *ZAP) ResolveRemove() (plan.RemovePlan, error) {
+	return plan.RemovePlan{
+		Product: z.Name(),
+		Paths:   plan.FromResolved(z.paths),
+	}, nil
+}
@@ -0,0 +1,27 @@
+package zap
+
+import (
+	"path/filepath"
+
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+)
+
+// logTail bounds how much of each log goes into a support bundle.
+const logTail = 1 << 20
+
+// logFiles are the logs the launchers write to the state directory.
+var logFiles = []string{"zap.log", "zap.err.log"}
+
+// SupportFiles lists ZAP's files worth attaching to a bug report: the
+// installed version marker and the end of each launcher log. Files that
+// don't exist are left out of the bundle.
+func (z *ZAP) SupportFiles() []plan.BundleFile {
+	files := []plan.BundleFile{
+		{Name: "markers/" + product.VersionMarkerFile, Path: filepath.Join(z.paths.InstallDir, product.VersionMarkerFile)},
+	}
+	for _, name := range logFiles {
+		files = append(files, plan.BundleFile{Name: "logs/" + name, Path: filepath.Join(z.paths.StateDir, name), Tail: logTail})
+	}
+	return files
+}
@@ -0,0 +1,82 @@
+package zap
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/plan"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+const (
+	// releaseURL is the GitHub release download base for ZAP.
+	releaseURL = "https://github.com/zaproxy/zaproxy/releases/download"
+
+	// archiveSpace is the space downloading and extracting the cross-platform
+	// release needs, with headroom. The archive is about 250 MB.
+	archiveSpace = 600 << 20
+)
+
+// releaseVersion matches ZAP release versions such as 2.15.0.
+var releaseVersion = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+func init() {
+	product.Register("zap", func(cfg config.Config, p paths.Paths) (product.Product, error) {
+		return New(cfg, p)
+	})
+}
+
+// ZAP encapsulates the resolution logic for OWASP ZAP.
+// No filesystem access, no network access - pure data transformation.
+type ZAP struct {
+	cfg   config.Config
+	paths paths.Paths
+}
+
+// New creates a ZAP product instance. ZAP releases are downloaded by
+// version, so product.version must name one.
+func New(cfg config.Config, p paths.Paths) (*ZAP, error) {
+	if cfg.Product.Name != "zap" {
+		return nil, fmt.Errorf("invalid product: expected zap, got %s", cfg.Product.Name)
+	}
+	if !releaseVersion.MatchString(cfg.Product.Version) {
+		return nil, fmt.Errorf("zap needs product.version set to a release such as 2.15.0, got %q", cfg.Product.Version)
+	}
+
+	return &ZAP{
+		cfg:   cfg,
+		paths: p,
+	}, nil
+}
+
+func (z *ZAP) Name() string {
+	return "zap"
+}
+
+// artifact returns the download artifact for the cross-platform release,
+// which unpacks to ZAP_<version> in the install dir.
+func (z *ZAP) artifact() plan.Artifact {
+	v := z.cfg.Product.Version
+	name := fmt.Sprintf("ZAP_%s_Crossplatform.zip", v)
+	return plan.Artifact{
+		Name:      name,
+		URL:       fmt.Sprintf("%s/v%s/%s", releaseURL, v, name),
+		Target:    filepath.Join(z.paths.InstallDir, name),
+		ExtractTo: z.paths.InstallDir,
+		Space:     archiveSpace,
+	}
+}
+
+// jar returns the path of the ZAP JAR for the configured version.
+func (z *ZAP) jar() string {
+	v := z.cfg.Product.Version
+	return filepath.Join(z.releaseDir(v), "zap-"+v+".jar")
+}
+
+// releaseDir returns the directory release v unpacks to.
+func (z *ZAP) releaseDir(v string) string {
+	return filepath.Join(z.paths.InstallDir, "ZAP_"+v)
+}
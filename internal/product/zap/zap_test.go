@@ -0,0 +1,107 @@
+package zap
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sdmrf/relay/internal/paths"
+	"github.com/sdmrf/relay/internal/product"
+	"github.com/sdmrf/relay/pkg/config"
+)
+
+func TestNewRequiresVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: "2.15.0"},
+		{version: "latest", wantErr: true},
+		{version: "", wantErr: true},
+		{version: "2.15", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Product.Name = "zap"
+			cfg.Product.Version = tt.version
+
+			_, err := New(cfg, paths.Paths{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cfg := config.Default()
+	cfg.Product.Name = "zap"
+	cfg.Product.Version = "2.15.0"
+	install := filepath.Join("opt", "relay")
+
+	z, err := New(cfg, paths.Paths{InstallDir: install})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installPlan, err := z.ResolveInstall()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := installPlan.Artifact
+	if want := "https://github.com/zaproxy/zaproxy/releases/download/v2.15.0/ZAP_2.15.0_Crossplatform.zip"; a.URL != want {
+		t.Errorf("URL = %s, want %s", a.URL, want)
+	}
+	if a.ExtractTo != install {
+		t.Errorf("ExtractTo = %s, want %s", a.ExtractTo, install)
+	}
+
+	launchPlan, err := z.ResolveLaunch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(install, "ZAP_2.15.0", "zap-2.15.0.jar"); launchPlan.Jar != want {
+		t.Errorf("Jar = %s, want %s", launchPlan.Jar, want)
+	}
+}
+
+func TestResolveUpdateReplaces(t *testing.T) {
+	tests := []struct {
+		installed string
+		want      string // Release directory the update replaces, under the install dir
+	}{
+		{installed: "2.14.0", want: "ZAP_2.14.0"},
+		{installed: "2.15.0"},
+		{installed: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.installed, func(t *testing.T) {
+			install := t.TempDir()
+			if err := product.WriteVersionMarker(install, tt.installed); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := config.Default()
+			cfg.Product.Name = "zap"
+			cfg.Product.Version = "2.15.0"
+			z, err := New(cfg, paths.Paths{InstallDir: install})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			updatePlan, err := z.ResolveUpdate()
+			if err != nil {
+				t.Fatalf("ResolveUpdate() error = %v", err)
+			}
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(install, tt.want)
+			}
+			if updatePlan.Replaces != want {
+				t.Errorf("Replaces = %q, want %q", updatePlan.Replaces, want)
+			}
+		})
+	}
+}
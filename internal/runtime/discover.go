@@ -0,0 +1,315 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Installation describes a Java runtime found on the system.
+type Installation struct {
+	Path    string // java executable
+	Home    string // java.home reported by the runtime
+	Vendor  string // java.vendor (e.g., "Eclipse Adoptium")
+	Version string // java.version (e.g., "21.0.5")
+	Major   int    // Feature version (e.g., 21)
+	Arch    string // os.arch (e.g., "amd64", "aarch64")
+	Source  string // Where it was found (JAVA_HOME, PATH, sdkman, ...)
+}
+
+// Source labels for discovered installations.
+const (
+	SourceBundled  = "bundled"
+	SourceJavaHome = "JAVA_HOME"
+	SourcePath     = "PATH"
+	SourceSystem   = "system"
+	SourceSDKMAN   = "sdkman"
+	SourceAsdf     = "asdf"
+	SourceHomebrew = "homebrew"
+)
+
+// probeTimeout bounds how long a single java binary may take to report its settings.
+const probeTimeout = 10 * time.Second
+
+// candidate is a java executable that has not been probed yet.
+type candidate struct {
+	path   string
+	source string
+}
+
+// Discoverer finds Java installations in well-known locations.
+type Discoverer struct {
+	InstallDir string                                  // Also report the bundled JRE in this directory (optional)
+	Getenv     func(string) string                     // Environment lookup (default: os.Getenv)
+	Home       string                                  // User home directory (default: os.UserHomeDir)
+	Probe      func(path string) (Installation, error) // Inspects a java binary (default: ProbeJava)
+}
+
+// Discover lists every Java installation found on the system, including
+// the bundled JRE in installDir when present.
+func Discover(installDir string) []Installation {
+	return Discoverer{InstallDir: installDir}.Discover()
+}
+
+// Discover probes every candidate and returns the installations that run.
+// Candidates resolving to the same binary are reported once, under the
+// first source that found them.
+func (d Discoverer) Discover() []Installation {
+	probe := d.Probe
+	if probe == nil {
+		probe = ProbeJava
+	}
+
+	seen := map[string]bool{}
+	var found []Installation
+
+	for _, c := range d.candidates() {
+		key := c.path
+		if real, err := filepath.EvalSymlinks(c.path); err == nil {
+			key = real
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		inst, err := probe(c.path)
+		if err != nil {
+			continue
+		}
+		inst.Source = c.source
+		found = append(found, inst)
+	}
+
+	return found
+}
+
+// candidates returns java executables in discovery order: bundled,
+// JAVA_HOME, PATH, then version-manager and OS-specific locations.
+func (d Discoverer) candidates() []candidate {
+	getenv := d.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	home := d.Home
+	if home == "" {
+		home, _ = os.UserHomeDir()
+	}
+
+	var out []candidate
+	addHome := func(javaHome, source string) {
+		bin := JavaHomeBinary(javaHome)
+		if isFile(bin) {
+			out = append(out, candidate{path: bin, source: source})
+		}
+	}
+	addGlob := func(pattern, source string) {
+		matches, _ := filepath.Glob(pattern)
+		sort.Strings(matches)
+		for _, m := range matches {
+			addHome(m, source)
+		}
+	}
+
+	if d.InstallDir != "" {
+		if bin := GetBundledJREPath(d.InstallDir); bin != "" {
+			out = append(out, candidate{path: bin, source: SourceBundled})
+		}
+	}
+
+	if javaHome := getenv("JAVA_HOME"); javaHome != "" {
+		addHome(javaHome, SourceJavaHome)
+	}
+
+	if bin := lookPath(getenv("PATH")); bin != "" {
+		out = append(out, candidate{path: bin, source: SourcePath})
+	}
+
+	// Version managers
+	if home != "" {
+		sdkman := getenv("SDKMAN_DIR")
+		if sdkman == "" {
+			sdkman = filepath.Join(home, ".sdkman")
+		}
+		addGlob(filepath.Join(sdkman, "candidates", "java", "*"), SourceSDKMAN)
+
+		asdf := getenv("ASDF_DATA_DIR")
+		if asdf == "" {
+			asdf = filepath.Join(home, ".asdf")
+		}
+		addGlob(filepath.Join(asdf, "installs", "java", "*"), SourceAsdf)
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		addGlob("/usr/lib/jvm/*", SourceSystem)
+		addGlob("/usr/java/*", SourceSystem)
+		addGlob("/opt/java/*", SourceSystem)
+		addGlob("/home/linuxbrew/.linuxbrew/opt/openjdk*/libexec", SourceHomebrew)
+	case "darwin":
+		addGlob("/Library/Java/JavaVirtualMachines/*/Contents/Home", SourceSystem)
+		if home != "" {
+			addGlob(filepath.Join(home, "Library", "Java", "JavaVirtualMachines", "*", "Contents", "Home"), SourceSystem)
+		}
+		addGlob("/opt/homebrew/opt/openjdk*/libexec/openjdk.jdk/Contents/Home", SourceHomebrew)
+		addGlob("/usr/local/opt/openjdk*/libexec/openjdk.jdk/Contents/Home", SourceHomebrew)
+	case "windows":
+		for _, env := range []string{"ProgramFiles", "ProgramW6432"} {
+			root := getenv(env)
+			if root == "" {
+				continue
+			}
+			for _, vendor := range []string{"Java", "Eclipse Adoptium", "Microsoft", "Zulu", "Amazon Corretto", "BellSoft"} {
+				addGlob(filepath.Join(root, vendor, "*"), SourceSystem)
+			}
+		}
+	}
+
+	return out
+}
+
+// lookPath finds java in the given PATH value without shelling out.
+func lookPath(pathEnv string) string {
+	name := "java"
+	if runtime.GOOS == "windows" {
+		name = "java.exe"
+	}
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		bin := filepath.Join(dir, name)
+		if isFile(bin) {
+			return bin
+		}
+	}
+	return ""
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// ProbeJava runs the java binary at path and reports its properties.
+func ProbeJava(path string) (Installation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, "-XshowSettings:properties", "-version")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return Installation{}, fmt.Errorf("run %s: %w", path, err)
+	}
+
+	return parseInstallation(path, stderr.String())
+}
+
+// parseInstallation builds an Installation from -XshowSettings:properties output.
+func parseInstallation(path, output string) (Installation, error) {
+	props := ParseJavaProperties(output)
+
+	major, err := ParseJavaVersion(output)
+	if err != nil {
+		return Installation{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return Installation{
+		Path:    path,
+		Home:    props["java.home"],
+		Vendor:  props["java.vendor"],
+		Version: props["java.version"],
+		Major:   major,
+		Arch:    props["os.arch"],
+	}, nil
+}
+
+// HostArch reports whether a Java os.arch value can run natively on this host.
+func HostArch(arch string) bool {
+	switch runtime.GOARCH {
+	case "amd64":
+		return arch == "amd64" || arch == "x86_64"
+	case "arm64":
+		return arch == "aarch64" || arch == "arm64"
+	case "386":
+		return arch == "x86" || arch == "i386"
+	default:
+		return arch == runtime.GOARCH
+	}
+}
+
+// Best returns the preferred installation meeting minVersion.
+// JAVA_HOME and PATH win when they qualify, since they reflect an explicit
+// user choice; otherwise the newest native installation is chosen.
+func Best(installs []Installation, minVersion int) (Installation, error) {
+	var eligible []Installation
+	for _, inst := range installs {
+		if inst.Major >= minVersion && (inst.Arch == "" || HostArch(inst.Arch)) {
+			eligible = append(eligible, inst)
+		}
+	}
+
+	if len(eligible) == 0 {
+		return Installation{}, fmt.Errorf("no java %d+ found (checked %d installations)", minVersion, len(installs))
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		pi, pj := sourceRank(eligible[i].Source), sourceRank(eligible[j].Source)
+		if pi != pj {
+			return pi < pj
+		}
+		return compareJavaVersions(eligible[i].Version, eligible[j].Version) > 0
+	})
+
+	return eligible[0], nil
+}
+
+func sourceRank(source string) int {
+	switch source {
+	case SourceJavaHome:
+		return 0
+	case SourcePath:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// compareJavaVersions compares java.version strings numerically.
+func compareJavaVersions(a, b string) int {
+	split := func(v string) []string {
+		return strings.FieldsFunc(v, func(r rune) bool {
+			return r == '.' || r == '_' || r == '+' || r == '-'
+		})
+	}
+
+	pa, pb := split(a), split(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			fmt.Sscanf(pa[i], "%d", &na)
+		}
+		if i < len(pb) {
+			fmt.Sscanf(pb[i], "%d", &nb)
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
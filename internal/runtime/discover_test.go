@@ -0,0 +1,150 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const propertiesOutput = `Property settings:
+    file.encoding = UTF-8
+    java.home = /usr/lib/jvm/temurin-21-jdk
+    java.library.path = /usr/java/packages/lib
+        /usr/lib64
+        /lib64
+    java.vendor = Eclipse Adoptium
+    java.version = 21.0.5
+    os.arch = amd64
+
+openjdk version "21.0.5" 2024-10-15 LTS
+OpenJDK Runtime Environment Temurin-21.0.5+11 (build 21.0.5+11-LTS)
+`
+
+func TestParseInstallation(t *testing.T) {
+	inst, err := parseInstallation("/usr/bin/java", propertiesOutput)
+	if err != nil {
+		t.Fatalf("parseInstallation() error = %v", err)
+	}
+
+	want := Installation{
+		Path:    "/usr/bin/java",
+		Home:    "/usr/lib/jvm/temurin-21-jdk",
+		Vendor:  "Eclipse Adoptium",
+		Version: "21.0.5",
+		Major:   21,
+		Arch:    "amd64",
+	}
+	if inst != want {
+		t.Errorf("parseInstallation() = %+v, want %+v", inst, want)
+	}
+}
+
+// fakeJavaHome creates a java binary under home and returns its path.
+func fakeJavaHome(t *testing.T, home string) string {
+	t.Helper()
+
+	bin := JavaHomeBinary(home)
+	if err := os.MkdirAll(filepath.Dir(bin), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(bin, []byte("fake java"), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return bin
+}
+
+func TestDiscovererDiscover(t *testing.T) {
+	tmp := t.TempDir()
+
+	javaHome := filepath.Join(tmp, "jdk-17")
+	javaHomeBin := fakeJavaHome(t, javaHome)
+	sdkmanBin := fakeJavaHome(t, filepath.Join(tmp, "sdkman", "candidates", "java", "21.0.5-tem"))
+	asdfBin := fakeJavaHome(t, filepath.Join(tmp, "asdf", "installs", "java", "temurin-11"))
+	brokenBin := fakeJavaHome(t, filepath.Join(tmp, "sdkman", "candidates", "java", "broken"))
+
+	env := map[string]string{
+		"JAVA_HOME":     javaHome,
+		"PATH":          filepath.Join(javaHome, "bin"), // Same binary as JAVA_HOME
+		"SDKMAN_DIR":    filepath.Join(tmp, "sdkman"),
+		"ASDF_DATA_DIR": filepath.Join(tmp, "asdf"),
+	}
+
+	versions := map[string]int{javaHomeBin: 17, sdkmanBin: 21, asdfBin: 11}
+
+	d := Discoverer{
+		Getenv: func(k string) string { return env[k] },
+		Home:   tmp,
+		Probe: func(path string) (Installation, error) {
+			major, ok := versions[path]
+			if !ok {
+				return Installation{}, fmt.Errorf("not java: %s", path)
+			}
+			return Installation{Path: path, Major: major, Version: fmt.Sprint(major)}, nil
+		},
+	}
+
+	got := map[string]string{}
+	for _, inst := range d.Discover() {
+		if _, dup := got[inst.Path]; dup {
+			t.Errorf("Discover() reported %s twice", inst.Path)
+		}
+		got[inst.Path] = inst.Source
+	}
+
+	want := map[string]string{
+		javaHomeBin: SourceJavaHome,
+		sdkmanBin:   SourceSDKMAN,
+		asdfBin:     SourceAsdf,
+	}
+	for path, source := range want {
+		if got[path] != source {
+			t.Errorf("Discover()[%s] source = %q, want %q", path, got[path], source)
+		}
+	}
+	if _, ok := got[brokenBin]; ok {
+		t.Error("Discover() should skip binaries that fail to probe")
+	}
+}
+
+func TestBest(t *testing.T) {
+	native := "amd64"
+	if runtime.GOARCH == "arm64" {
+		native = "aarch64"
+	}
+
+	installs := []Installation{
+		{Path: "/path/java", Major: 11, Version: "11.0.20", Arch: native, Source: SourcePath},
+		{Path: "/sdkman/17", Major: 17, Version: "17.0.8", Arch: native, Source: SourceSDKMAN},
+		{Path: "/sdkman/21", Major: 21, Version: "21.0.1", Arch: native, Source: SourceSDKMAN},
+		{Path: "/system/21", Major: 21, Version: "21.0.5", Arch: native, Source: SourceSystem},
+		{Path: "/foreign/23", Major: 23, Version: "23.0.1", Arch: "ppc64le", Source: SourceSystem},
+	}
+
+	tests := []struct {
+		name     string
+		installs []Installation
+		min      int
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "newest qualifying version", installs: installs, min: 17, wantPath: "/system/21"},
+		{name: "PATH preferred when it qualifies", installs: installs, min: 11, wantPath: "/path/java"},
+		{name: "foreign architecture skipped", installs: installs[4:], min: 17, wantErr: true},
+		{name: "nothing meets minimum", installs: installs, min: 25, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Best(tt.installs, tt.min)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Best() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Path != tt.wantPath {
+				t.Errorf("Best() = %v, want %v", got.Path, tt.wantPath)
+			}
+		})
+	}
+}
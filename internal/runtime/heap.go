@@ -0,0 +1,197 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	mib = 1 << 20
+	gib = 1 << 30
+
+	// Bounds for the "auto" heap: below 1 GiB Burp struggles to start,
+	// above 32 GiB the JVM loses compressed object pointers. The minimum
+	// gives way to a smaller memory limit, since a heap past the limit
+	// gets the JVM killed.
+	autoHeapMin = 1 * gib
+	autoHeapMax = 32 * gib
+
+	// minHeap is the smallest heap a percentage expression may produce.
+	minHeap = 256 * mib
+)
+
+// IsHeapExpression reports whether expr is a heap value relay expands at
+// launch time ("auto" or a percentage such as "50%").
+func IsHeapExpression(expr string) bool {
+	expr = strings.TrimSpace(expr)
+	return expr == "auto" || strings.HasSuffix(expr, "%")
+}
+
+// HeapSize resolves a heap value to bytes given the memory limit.
+// Accepts "auto" (half the limit, clamped to 1-32 GiB but never above
+// three quarters of the limit), a percentage of the limit ("50%") or a
+// JVM size ("4g", "4096m").
+func HeapSize(expr string, limit uint64) (uint64, error) {
+	expr = strings.TrimSpace(expr)
+
+	switch {
+	case expr == "auto":
+		if limit == 0 {
+			return 0, fmt.Errorf("heap auto: host memory unknown")
+		}
+		size := limit / 2
+		if size < autoHeapMin {
+			// Leave a quarter of the limit for the JVM's own memory
+			size = max(size, min(autoHeapMin, limit/4*3/mib*mib))
+		}
+		if size > autoHeapMax {
+			size = autoHeapMax
+		}
+		return size, nil
+
+	case strings.HasSuffix(expr, "%"):
+		pct, err := strconv.Atoi(strings.TrimSuffix(expr, "%"))
+		if err != nil || pct < 1 || pct > 100 {
+			return 0, fmt.Errorf("invalid heap percentage: %s", expr)
+		}
+		if limit == 0 {
+			return 0, fmt.Errorf("heap %s: host memory unknown", expr)
+		}
+		size := limit * uint64(pct) / 100 / mib * mib
+		if size < minHeap {
+			size = min(minHeap, limit/mib*mib)
+		}
+		return size, nil
+
+	default:
+		return ParseMemorySize(expr)
+	}
+}
+
+// DropHeapExpressions removes the heap expressions from args and heap,
+// keeping concrete sizes, for when host memory can't be detected and the
+// JVM's default heap has to do.
+func DropHeapExpressions(args []string, heap string) ([]string, string) {
+	if IsHeapExpression(heap) {
+		heap = ""
+	}
+
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		if v, ok := strings.CutPrefix(arg, "-Xmx"); ok && IsHeapExpression(v) {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out, heap
+}
+
+// ParseMemorySize parses a JVM memory size such as "4g", "512m" or "1048576".
+func ParseMemorySize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty memory size")
+	}
+
+	multiplier := uint64(1)
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		multiplier = 1 << 10
+	case 'm', 'M':
+		multiplier = mib
+	case 'g', 'G':
+		multiplier = gib
+	case 't', 'T':
+		multiplier = 1 << 40
+	}
+	if multiplier != 1 {
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory size: %s", s)
+	}
+	return n * multiplier, nil
+}
+
+// FormatMemorySize formats bytes as a JVM size, using "g" when exact.
+func FormatMemorySize(b uint64) string {
+	if b%gib == 0 {
+		return fmt.Sprintf("%dg", b/gib)
+	}
+	return fmt.Sprintf("%dm", b/mib)
+}
+
+// ExpandJVMArgs resolves heap expressions into concrete -Xmx flags.
+// Expressions come from heap (runtime.java.heap) or from -Xmx entries in
+// args such as "-Xmx50%" or "-Xmxauto"; heap takes precedence and replaces
+// any -Xmx in args. An -Xms larger than the resolved maximum is lowered to
+// match so the JVM can start.
+func ExpandJVMArgs(args []string, heap string, limit uint64) ([]string, error) {
+	out := make([]string, 0, len(args)+1)
+	var xmx uint64
+
+	for _, arg := range args {
+		value, ok := strings.CutPrefix(arg, "-Xmx")
+		if !ok {
+			out = append(out, arg)
+			continue
+		}
+		if heap != "" {
+			continue // Replaced by runtime.java.heap below
+		}
+
+		size, err := HeapSize(value, limit)
+		if err != nil {
+			return nil, fmt.Errorf("jvm_args %s: %w", arg, err)
+		}
+		xmx = size
+		out = append(out, "-Xmx"+FormatMemorySize(size))
+	}
+
+	if heap != "" {
+		size, err := HeapSize(heap, limit)
+		if err != nil {
+			return nil, fmt.Errorf("runtime.java.heap: %w", err)
+		}
+		xmx = size
+		out = append(out, "-Xmx"+FormatMemorySize(size))
+	}
+
+	if xmx == 0 {
+		return out, nil
+	}
+
+	for i, arg := range out {
+		value, ok := strings.CutPrefix(arg, "-Xms")
+		if !ok {
+			continue
+		}
+		if xms, err := ParseMemorySize(value); err == nil && xms > xmx {
+			out[i] = "-Xms" + FormatMemorySize(xmx)
+		}
+	}
+
+	return out, nil
+}
+
+// MaxHeap returns the -Xmx the given args and heap setting resolve to,
+// or 0 if no maximum heap is configured.
+func MaxHeap(args []string, heap string, limit uint64) (uint64, error) {
+	expanded, err := ExpandJVMArgs(args, heap, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var xmx uint64
+	for _, arg := range expanded {
+		if value, ok := strings.CutPrefix(arg, "-Xmx"); ok {
+			if xmx, err = ParseMemorySize(value); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return xmx, nil
+}
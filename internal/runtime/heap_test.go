@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeapSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		limit   uint64
+		want    uint64
+		wantErr bool
+	}{
+		{name: "auto on 8GB laptop", expr: "auto", limit: 8 * gib, want: 4 * gib},
+		{name: "auto clamps to minimum", expr: "auto", limit: 1536 * mib, want: 1 * gib},
+		{name: "auto minimum leaves room on 1GB", expr: "auto", limit: 1 * gib, want: 768 * mib},
+		{name: "auto stays under small cgroup limit", expr: "auto", limit: 512 * mib, want: 384 * mib},
+		{name: "auto clamps to maximum", expr: "auto", limit: 128 * gib, want: 32 * gib},
+		{name: "percentage", expr: "50%", limit: 64 * gib, want: 32 * gib},
+		{name: "percentage floor", expr: "1%", limit: 4 * gib, want: 256 * mib},
+		{name: "percentage floor capped at limit", expr: "1%", limit: 128 * mib, want: 128 * mib},
+		{name: "fixed size", expr: "4g", limit: 0, want: 4 * gib},
+		{name: "fixed size in megabytes", expr: "1536m", limit: 0, want: 1536 * mib},
+		{name: "auto without memory", expr: "auto", limit: 0, wantErr: true},
+		{name: "percentage out of range", expr: "150%", limit: 8 * gib, wantErr: true},
+		{name: "garbage", expr: "lots", limit: 8 * gib, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HeapSize(tt.expr, tt.limit)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HeapSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("HeapSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandJVMArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		heap  string
+		limit uint64
+		want  []string
+	}{
+		{
+			name: "no heap expressions",
+			args: []string{"-Xmx4g", "-XX:+UseG1GC"},
+			want: []string{"-Xmx4g", "-XX:+UseG1GC"},
+		},
+		{
+			name:  "heap setting appends -Xmx",
+			args:  []string{"-Xms1g", "-XX:+UseG1GC"},
+			heap:  "auto",
+			limit: 16 * gib,
+			want:  []string{"-Xms1g", "-XX:+UseG1GC", "-Xmx8g"},
+		},
+		{
+			name:  "heap setting replaces -Xmx in args",
+			args:  []string{"-Xmx4g", "-XX:+UseG1GC"},
+			heap:  "25%",
+			limit: 64 * gib,
+			want:  []string{"-XX:+UseG1GC", "-Xmx16g"},
+		},
+		{
+			name:  "percentage in jvm_args",
+			args:  []string{"-Xmx50%"},
+			limit: 6 * gib,
+			want:  []string{"-Xmx3g"},
+		},
+		{
+			name:  "-Xms lowered to fit maximum",
+			args:  []string{"-Xms4g", "-Xmxauto"},
+			limit: 4 * gib,
+			want:  []string{"-Xms2g", "-Xmx2g"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandJVMArgs(tt.args, tt.heap, tt.limit)
+			if err != nil {
+				t.Fatalf("ExpandJVMArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandJVMArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxHeap(t *testing.T) {
+	got, err := MaxHeap([]string{"-Xms1g"}, "", 8*gib)
+	if err != nil {
+		t.Fatalf("MaxHeap() error = %v", err)
+	}
+	if got != 0 {
+		t.Errorf("MaxHeap() = %v, want 0 when no maximum configured", got)
+	}
+
+	got, err = MaxHeap([]string{"-Xmx2g"}, "50%", 8*gib)
+	if err != nil {
+		t.Fatalf("MaxHeap() error = %v", err)
+	}
+	if got != 4*gib {
+		t.Errorf("MaxHeap() = %v, want %v", got, uint64(4*gib))
+	}
+}
+
+func TestDropHeapExpressions(t *testing.T) {
+	args, heap := DropHeapExpressions([]string{"-Xms1g", "-Xmxauto", "-Xmx50%", "-Xmx2g"}, "auto")
+	if want := []string{"-Xms1g", "-Xmx2g"}; !reflect.DeepEqual(args, want) || heap != "" {
+		t.Errorf("DropHeapExpressions() = %v, %q; want %v, \"\"", args, heap, want)
+	}
+
+	if _, heap := DropHeapExpressions(nil, "4g"); heap != "4g" {
+		t.Errorf("DropHeapExpressions() heap = %q, want 4g kept", heap)
+	}
+}
@@ -30,15 +30,25 @@ func ValidateJava(minVersion int) (string, error) {
 	return info.Output, nil
 }
 
-// GetJavaInfo retrieves information about the installed Java.
+// GetJavaInfo retrieves information about the Java on PATH.
 func GetJavaInfo() (JavaInfo, error) {
-	cmd := exec.Command("java", "-version")
+	path, err := exec.LookPath("java")
+	if err != nil {
+		return JavaInfo{}, fmt.Errorf("java not found in PATH")
+	}
+
+	return GetJavaInfoAt(path)
+}
+
+// GetJavaInfoAt retrieves information about the Java binary at path.
+func GetJavaInfoAt(path string) (JavaInfo, error) {
+	cmd := exec.Command(path, "-version")
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return JavaInfo{}, fmt.Errorf("java not found in PATH")
+		return JavaInfo{}, fmt.Errorf("run %s -version: %w", path, err)
 	}
 
 	output := stderr.String()
@@ -48,8 +58,6 @@ func GetJavaInfo() (JavaInfo, error) {
 		return JavaInfo{}, err
 	}
 
-	path := getJavaPath()
-
 	return JavaInfo{
 		Version: version,
 		Path:    path,
@@ -57,12 +65,18 @@ func GetJavaInfo() (JavaInfo, error) {
 	}, nil
 }
 
-// ParseJavaVersion extracts the major version number from java -version output.
+// ParseJavaVersion extracts the major version number from java -version
+// or java -XshowSettings:properties -version output.
 func ParseJavaVersion(output string) (int, error) {
 	// Handles formats:
 	// - java version "17.0.8"
 	// - openjdk version "21.0.1"
 	// - java version "1.8.0_392" (Java 8)
+	// - java.version = 17.0.8 (-XshowSettings:properties)
+	if v, ok := ParseJavaProperties(output)["java.version"]; ok {
+		return majorVersion(v)
+	}
+
 	start := strings.Index(output, `"`)
 	if start == -1 {
 		return 0, fmt.Errorf("unable to parse java version")
@@ -73,7 +87,18 @@ func ParseJavaVersion(output string) (int, error) {
 		return 0, fmt.Errorf("unable to parse java version")
 	}
 
-	raw := output[start+1 : start+1+end]
+	return majorVersion(output[start+1 : start+1+end])
+}
+
+// majorVersion returns the feature version of a java.version string.
+func majorVersion(raw string) (int, error) {
+	raw = strings.TrimSpace(raw)
+
+	// Early-access builds report versions like "22-ea"
+	if i := strings.IndexAny(raw, "-+_"); i != -1 && !strings.HasPrefix(raw, "1.") {
+		raw = raw[:i]
+	}
+
 	parts := strings.Split(raw, ".")
 
 	// Handle 1.8 style versions (Java 8)
@@ -84,12 +109,25 @@ func ParseJavaVersion(output string) (int, error) {
 	return strconv.Atoi(parts[0])
 }
 
-// getJavaPath attempts to find the java executable path.
-func getJavaPath() string {
-	cmd := exec.Command("which", "java")
-	out, err := cmd.Output()
-	if err != nil {
-		return ""
+// ParseJavaProperties extracts system properties from
+// java -XshowSettings:properties output. Multi-line values keep their first line.
+func ParseJavaProperties(output string) map[string]string {
+	props := map[string]string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		// Properties are indented four spaces; continuation lines are indented further
+		if !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+		if !ok {
+			continue
+		}
+		props[key] = strings.TrimSpace(value)
 	}
-	return strings.TrimSpace(string(out))
+
+	return props
 }
@@ -44,6 +44,21 @@ func TestParseJavaVersion(t *testing.T) {
 			output: `openjdk version "21" 2023-09-19`,
 			want:   21,
 		},
+		{
+			name:   "Properties format",
+			output: "Property settings:\n    java.vendor = Eclipse Adoptium\n    java.version = 17.0.8\n",
+			want:   17,
+		},
+		{
+			name:   "Properties format Java 8",
+			output: "Property settings:\n    java.version = 1.8.0_392\n",
+			want:   8,
+		},
+		{
+			name:   "Early access build",
+			output: `openjdk version "24-ea" 2024-09-17`,
+			want:   24,
+		},
 		{
 			name:    "No quotes",
 			output:  `openjdk version 17.0.8`,
@@ -5,19 +5,22 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
-// JRE version to bundle - Eclipse Temurin 21 LTS
-const (
-	JREVersion      = "21.0.5"
-	JREBuildNumber  = "11"
-	JREMajorVersion = 21
-)
+// DefaultJREFeature is the Temurin feature version bundled when
+// runtime.java.bundled.version is not set.
+const DefaultJREFeature = 21
+
+// jreMarkerFile records the installed bundled JRE release.
+const jreMarkerFile = ".relay-jre-version"
 
 // JREArtifact represents a downloadable JRE.
 type JREArtifact struct {
 	Name      string // Display name
+	Version   string // Release version (e.g., "21.0.5+11")
 	URL       string // Download URL
+	Checksum  string // Expected SHA-256 of the archive (optional)
 	Target    string // Download target path (.tar.gz or .zip)
 	ExtractTo string // Extraction destination directory
 }
@@ -57,118 +60,226 @@ func HasBundledJRE(installDir string) bool {
 	return GetBundledJREPath(installDir) != ""
 }
 
-// HasSystemJava checks if Java is available on the system PATH.
-func HasSystemJava() bool {
-	_, err := GetJavaInfo()
+// HasSystemJava checks if a system Java meeting minVersion can be discovered.
+func HasSystemJava(minVersion int) bool {
+	_, err := systemJava(JavaOptions{MinVersion: minVersion})
 	return err == nil
 }
 
+// systemJava returns the best discovered system Java meeting
+// opts.MinVersion, probing candidates with opts.Prober.
+func systemJava(opts JavaOptions) (Installation, error) {
+	return Best(Discoverer{Probe: opts.Prober.Probe}.Discover(), opts.MinVersion)
+}
+
+// Java strategies accepted by ResolveJavaPath and NeedsJRE.
+// Mirrors config.JavaStrategy without importing the config package.
+const (
+	StrategyAuto    = "auto"    // Prefer bundled, fallback to system
+	StrategySystem  = "system"  // Only use system Java
+	StrategyBundled = "bundled" // Only use bundled JRE
+	StrategyPath    = "path"    // Only use the Java in JavaHome
+	StrategyManaged = "managed" // Bundled JRE installed by relay and pinned to a release
+)
+
+// JavaOptions selects which Java binary to use.
+type JavaOptions struct {
+	Strategy   string // One of the Strategy* constants
+	InstallDir string // Location of the bundled JRE
+	JavaHome   string // Java home directory for StrategyPath
+	Pin        string // Required bundled JRE release for StrategyManaged
+	MinVersion int    // Minimum version when choosing among system installations
+	Prober     Prober // Probes system installations; give it a CacheFile to skip unchanged ones
+}
+
 // ResolveJavaPath determines which Java to use based on strategy.
 // Returns the path to the java binary.
-func ResolveJavaPath(installDir string, strategy string) (string, error) {
-	switch strategy {
-	case "system":
+func ResolveJavaPath(opts JavaOptions) (string, error) {
+	switch opts.Strategy {
+	case StrategySystem:
 		// Only use system Java
-		info, err := GetJavaInfo()
+		inst, err := systemJava(opts)
 		if err != nil {
 			return "", fmt.Errorf("system java required but not found: %w", err)
 		}
-		return info.Path, nil
+		return inst.Path, nil
 
-	case "bundled":
+	case StrategyBundled:
 		// Only use bundled JRE
-		path := GetBundledJREPath(installDir)
+		path := GetBundledJREPath(opts.InstallDir)
+		if path == "" {
+			return "", fmt.Errorf("bundled JRE required but not found in %s", opts.InstallDir)
+		}
+		return path, nil
+
+	case StrategyPath:
+		// Only use the configured java_home
+		path := JavaHomeBinary(opts.JavaHome)
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("java not found in java_home %s: %w", opts.JavaHome, err)
+		}
+		return path, nil
+
+	case StrategyManaged:
+		// Bundled JRE at exactly the pinned release
+		path := GetBundledJREPath(opts.InstallDir)
 		if path == "" {
-			return "", fmt.Errorf("bundled JRE required but not found in %s", installDir)
+			return "", fmt.Errorf("managed JRE not installed in %s (run 'relay install')", opts.InstallDir)
+		}
+		ok, installed, err := matchesPin(opts)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", fmt.Errorf("managed JRE is %s, pinned to %s (run 'relay jre update')", displayRelease(installed), pinVersion(opts.Pin))
 		}
 		return path, nil
 
-	default: // "auto"
+	case StrategyAuto:
 		// Prefer bundled, fallback to system
-		if path := GetBundledJREPath(installDir); path != "" {
+		if path := GetBundledJREPath(opts.InstallDir); path != "" {
 			return path, nil
 		}
-		info, err := GetJavaInfo()
+		inst, err := systemJava(opts)
 		if err != nil {
-			return "", fmt.Errorf("no java found (bundled or system)")
+			return "", fmt.Errorf("no java found (bundled or system): %w", err)
 		}
-		return info.Path, nil
+		return inst.Path, nil
+
+	default:
+		return "", fmt.Errorf("unknown java strategy: %q", opts.Strategy)
 	}
 }
 
 // NeedsJRE determines if JRE needs to be downloaded.
-// Returns true if no Java is available (bundled or system).
-func NeedsJRE(installDir string, strategy string) bool {
-	switch strategy {
-	case "system":
-		// Never download JRE if system-only strategy
-		return false
-	case "bundled":
+// Returns true if the strategy requires a bundled JRE that is missing
+// (or, for StrategyManaged, not at the pinned release).
+func NeedsJRE(opts JavaOptions) (bool, error) {
+	switch opts.Strategy {
+	case StrategySystem, StrategyPath:
+		// Never download JRE if relay doesn't manage Java
+		return false, nil
+	case StrategyBundled:
 		// Need JRE if bundled doesn't exist
-		return !HasBundledJRE(installDir)
-	default: // "auto"
+		return !HasBundledJRE(opts.InstallDir), nil
+	case StrategyManaged:
+		// Need JRE if missing or drifted from the pin
+		if !HasBundledJRE(opts.InstallDir) {
+			return true, nil
+		}
+		ok, _, err := matchesPin(opts)
+		return !ok, err
+	case StrategyAuto:
 		// Need JRE if neither bundled nor system available
-		return !HasBundledJRE(installDir) && !HasSystemJava()
+		if HasBundledJRE(opts.InstallDir) {
+			return false, nil
+		}
+		_, err := systemJava(opts)
+		return err != nil, nil
+	default:
+		return false, fmt.Errorf("unknown java strategy: %q", opts.Strategy)
+	}
+}
+
+// JavaHomeBinary returns the java executable inside a Java home directory.
+func JavaHomeBinary(home string) string {
+	name := "java"
+	if runtime.GOOS == "windows" {
+		name = "java.exe"
 	}
+	return filepath.Join(home, "bin", name)
 }
 
-// BuildJREArtifact creates a JREArtifact for the current platform.
-func BuildJREArtifact(installDir string) (JREArtifact, error) {
-	url, ext, err := jreDownloadURL()
+// matchesPin reports whether the installed bundled JRE is the pinned release.
+func matchesPin(opts JavaOptions) (bool, string, error) {
+	spec, err := ParseJRESpec(opts.Pin)
+	if err != nil {
+		return false, "", fmt.Errorf("invalid managed JRE pin: %w", err)
+	}
+	if !spec.Exact() {
+		return false, "", fmt.Errorf("managed JRE pin must be an exact release, got %q", opts.Pin)
+	}
+
+	installed, err := ReadJREMarker(opts.InstallDir)
+	if err != nil {
+		return false, "", fmt.Errorf("read JRE marker: %w", err)
+	}
+
+	return installed == pinVersion(opts.Pin), installed, nil
+}
+
+// pinVersion normalizes an exact release selector to the marker format.
+func pinVersion(pin string) string {
+	return strings.TrimPrefix(strings.TrimSpace(pin), "jdk-")
+}
+
+func displayRelease(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
+
+// BuildJREArtifact creates a JREArtifact for a resolved release.
+func BuildJREArtifact(installDir string, rel JRERelease) (JREArtifact, error) {
+	ext, err := archiveExt(rel.FileName)
 	if err != nil {
 		return JREArtifact{}, err
 	}
 
-	archiveName := fmt.Sprintf("jre-%s%s", JREVersion, ext)
+	archiveName := fmt.Sprintf("jre-%s%s", rel.Version, ext)
 
 	return JREArtifact{
-		Name:      fmt.Sprintf("Eclipse Temurin JRE %s", JREVersion),
-		URL:       url,
+		Name:      fmt.Sprintf("Eclipse Temurin JRE %s", rel.Version),
+		Version:   rel.Version,
+		URL:       rel.URL,
+		Checksum:  rel.Checksum,
 		Target:    filepath.Join(installDir, archiveName),
 		ExtractTo: installDir,
 	}, nil
 }
 
-// jreDownloadURL returns the Adoptium download URL for the current platform.
-func jreDownloadURL() (url string, ext string, err error) {
-	var osName, archName string
-
-	switch runtime.GOOS {
-	case "linux":
-		osName = "linux"
-		ext = ".tar.gz"
-	case "darwin":
-		osName = "mac"
-		ext = ".tar.gz"
-	case "windows":
-		osName = "windows"
-		ext = ".zip"
+// archiveExt returns the archive extension of a release package name.
+func archiveExt(name string) (string, error) {
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"):
+		return ".tar.gz", nil
+	case strings.HasSuffix(name, ".zip"):
+		return ".zip", nil
 	default:
-		return "", "", fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+		return "", fmt.Errorf("unsupported JRE package: %s", name)
 	}
+}
 
-	switch runtime.GOARCH {
-	case "amd64":
-		archName = "x64"
-	case "arm64":
-		archName = "aarch64"
-	default:
-		return "", "", fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
-	}
+// BundledJREDir returns the directory the bundled JRE is extracted to.
+func BundledJREDir(installDir string) string {
+	return filepath.Join(installDir, "jre")
+}
 
-	// Eclipse Adoptium Temurin URL pattern
-	// Example: https://github.com/adoptium/temurin21-binaries/releases/download/jdk-21.0.5+11/OpenJDK21U-jre_x64_mac_hotspot_21.0.5_11.tar.gz
-	baseURL := "https://github.com/adoptium/temurin21-binaries/releases/download"
-	tag := fmt.Sprintf("jdk-%s+%s", JREVersion, JREBuildNumber)
-	fileName := fmt.Sprintf("OpenJDK21U-jre_%s_%s_hotspot_%s_%s%s",
-		archName, osName, JREVersion, JREBuildNumber, ext)
+// ReadJREMarker returns the bundled JRE release recorded in installDir.
+// Returns an empty string if no marker exists.
+func ReadJREMarker(installDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(installDir, jreMarkerFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
 
-	url = fmt.Sprintf("%s/%s/%s", baseURL, tag, fileName)
-	return url, ext, nil
+// WriteJREMarker records the bundled JRE release in installDir.
+func WriteJREMarker(installDir, version string) error {
+	markerPath := filepath.Join(installDir, jreMarkerFile)
+	return os.WriteFile(markerPath, []byte(version+"\n"), 0o644)
 }
 
-// JREExtractedDir returns the expected directory name after extraction.
-// Adoptium archives extract to a directory like "jdk-21.0.5+11-jre"
-func JREExtractedDir() string {
-	return fmt.Sprintf("jdk-%s+%s-jre", JREVersion, JREBuildNumber)
+// RemoveJREMarker deletes the bundled JRE marker, if present.
+func RemoveJREMarker(installDir string) error {
+	err := os.Remove(filepath.Join(installDir, jreMarkerFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
 }
@@ -7,49 +7,6 @@ import (
 	"testing"
 )
 
-func TestJREDownloadURL(t *testing.T) {
-	url, ext, err := jreDownloadURL()
-	if err != nil {
-		// Skip on unsupported platforms
-		if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
-			t.Skip("unsupported platform")
-		}
-		if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
-			t.Skip("unsupported architecture")
-		}
-		t.Fatalf("jreDownloadURL() error = %v", err)
-	}
-
-	// Check URL format
-	if url == "" {
-		t.Error("jreDownloadURL() returned empty URL")
-	}
-
-	// Check extension
-	if runtime.GOOS == "windows" {
-		if ext != ".zip" {
-			t.Errorf("jreDownloadURL() ext = %v, want .zip on Windows", ext)
-		}
-	} else {
-		if ext != ".tar.gz" {
-			t.Errorf("jreDownloadURL() ext = %v, want .tar.gz on Unix", ext)
-		}
-	}
-
-	// Verify URL contains expected components
-	expectedComponents := []string{
-		"adoptium",
-		"temurin21",
-		"OpenJDK21U-jre",
-		JREVersion,
-	}
-	for _, comp := range expectedComponents {
-		if !contains(url, comp) {
-			t.Errorf("jreDownloadURL() URL missing %q: %s", comp, url)
-		}
-	}
-}
-
 func TestGetBundledJREPath(t *testing.T) {
 	// Create a temp directory structure
 	tmpDir := t.TempDir()
@@ -130,9 +87,15 @@ func TestResolveJavaPath(t *testing.T) {
 		t.Fatalf("WriteFile() error = %v", err)
 	}
 
+	if err := WriteJREMarker(installDir, "21.0.5+11"); err != nil {
+		t.Fatalf("WriteJREMarker() error = %v", err)
+	}
+
 	tests := []struct {
 		name       string
 		strategy   string
+		javaHome   string
+		pin        string
 		wantPath   string
 		wantErr    bool
 		skipReason string
@@ -149,6 +112,41 @@ func TestResolveJavaPath(t *testing.T) {
 			wantPath: javaBin,
 			wantErr:  false,
 		},
+		{
+			name:     "path strategy with java_home",
+			strategy: "path",
+			javaHome: filepath.Join(installDir, "jre"),
+			wantPath: javaBin,
+		},
+		{
+			name:     "path strategy with missing java_home",
+			strategy: "path",
+			javaHome: filepath.Join(tmpDir, "missing"),
+			wantErr:  true,
+		},
+		{
+			name:     "managed strategy at pinned release",
+			strategy: "managed",
+			pin:      "jdk-21.0.5+11",
+			wantPath: javaBin,
+		},
+		{
+			name:     "managed strategy drifted from pin",
+			strategy: "managed",
+			pin:      "21.0.6+7",
+			wantErr:  true,
+		},
+		{
+			name:     "managed strategy with feature version pin",
+			strategy: "managed",
+			pin:      "21",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown strategy",
+			strategy: "bundeld",
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,7 +155,12 @@ func TestResolveJavaPath(t *testing.T) {
 				t.Skip(tt.skipReason)
 			}
 
-			got, err := ResolveJavaPath(installDir, tt.strategy)
+			got, err := ResolveJavaPath(JavaOptions{
+				Strategy:   tt.strategy,
+				InstallDir: installDir,
+				JavaHome:   tt.javaHome,
+				Pin:        tt.pin,
+			})
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ResolveJavaPath() error = %v, wantErr %v", err, tt.wantErr)
@@ -195,6 +198,12 @@ func TestNeedsJRE(t *testing.T) {
 			hasJRE:    false,
 			wantNeeds: false,
 		},
+		{
+			name:      "path strategy never needs bundled",
+			strategy:  "path",
+			hasJRE:    false,
+			wantNeeds: false,
+		},
 		{
 			name:      "bundled strategy needs JRE when not present",
 			strategy:  "bundled",
@@ -227,7 +236,10 @@ func TestNeedsJRE(t *testing.T) {
 				}
 			}
 
-			got := NeedsJRE(testDir, tt.strategy)
+			got, err := NeedsJRE(JavaOptions{Strategy: tt.strategy, InstallDir: testDir})
+			if err != nil {
+				t.Fatalf("NeedsJRE() error = %v", err)
+			}
 			if got != tt.wantNeeds {
 				t.Errorf("NeedsJRE() = %v, want %v", got, tt.wantNeeds)
 			}
@@ -235,45 +247,112 @@ func TestNeedsJRE(t *testing.T) {
 	}
 }
 
+func TestNeedsJREManaged(t *testing.T) {
+	installDir := t.TempDir()
+	opts := JavaOptions{Strategy: "managed", InstallDir: installDir, Pin: "21.0.5+11"}
+
+	if got, err := NeedsJRE(opts); err != nil || !got {
+		t.Errorf("NeedsJRE() = %v, %v; want true when JRE not present", got, err)
+	}
+
+	javaBin := JavaHomeBinary(filepath.Join(installDir, "jre"))
+	if err := os.MkdirAll(filepath.Dir(javaBin), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(javaBin, []byte("fake java"), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := WriteJREMarker(installDir, "21.0.4+7"); err != nil {
+		t.Fatalf("WriteJREMarker() error = %v", err)
+	}
+
+	if got, err := NeedsJRE(opts); err != nil || !got {
+		t.Errorf("NeedsJRE() = %v, %v; want true when JRE drifted from pin", got, err)
+	}
+
+	if err := WriteJREMarker(installDir, "21.0.5+11"); err != nil {
+		t.Fatalf("WriteJREMarker() error = %v", err)
+	}
+
+	if got, err := NeedsJRE(opts); err != nil || got {
+		t.Errorf("NeedsJRE() = %v, %v; want false at pinned release", got, err)
+	}
+}
+
+func TestNeedsJREUnknownStrategy(t *testing.T) {
+	if _, err := NeedsJRE(JavaOptions{Strategy: "", InstallDir: t.TempDir()}); err == nil {
+		t.Error("NeedsJRE() expected error for unknown strategy")
+	}
+}
+
 func TestBuildJREArtifact(t *testing.T) {
 	tmpDir := t.TempDir()
 	installDir := filepath.Join(tmpDir, "relay")
 
-	artifact, err := BuildJREArtifact(installDir)
+	rel := JRERelease{
+		Feature:  21,
+		Version:  "21.0.5+11",
+		Release:  "jdk-21.0.5+11",
+		URL:      "https://example.com/OpenJDK21U-jre_x64_linux_hotspot_21.0.5_11.tar.gz",
+		FileName: "OpenJDK21U-jre_x64_linux_hotspot_21.0.5_11.tar.gz",
+		Checksum: "abc123",
+	}
+
+	artifact, err := BuildJREArtifact(installDir, rel)
 	if err != nil {
-		// Skip on unsupported platforms
-		if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
-			t.Skip("unsupported platform")
-		}
-		if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
-			t.Skip("unsupported architecture")
-		}
 		t.Fatalf("BuildJREArtifact() error = %v", err)
 	}
 
-	if artifact.Name == "" {
-		t.Error("BuildJREArtifact() Name is empty")
+	if !contains(artifact.Name, rel.Version) {
+		t.Errorf("BuildJREArtifact() Name = %v, should contain %v", artifact.Name, rel.Version)
+	}
+	if artifact.URL != rel.URL {
+		t.Errorf("BuildJREArtifact() URL = %v, want %v", artifact.URL, rel.URL)
 	}
-	if artifact.URL == "" {
-		t.Error("BuildJREArtifact() URL is empty")
+	if artifact.Checksum != rel.Checksum {
+		t.Errorf("BuildJREArtifact() Checksum = %v, want %v", artifact.Checksum, rel.Checksum)
 	}
-	if artifact.Target == "" {
-		t.Error("BuildJREArtifact() Target is empty")
+	if want := filepath.Join(installDir, "jre-21.0.5+11.tar.gz"); artifact.Target != want {
+		t.Errorf("BuildJREArtifact() Target = %v, want %v", artifact.Target, want)
 	}
 	if artifact.ExtractTo != installDir {
 		t.Errorf("BuildJREArtifact() ExtractTo = %v, want %v", artifact.ExtractTo, installDir)
 	}
+
+	rel.FileName = "OpenJDK21U-jre_x64_linux_hotspot_21.0.5_11.msi"
+	if _, err := BuildJREArtifact(installDir, rel); err == nil {
+		t.Error("BuildJREArtifact() expected error for unsupported package type")
+	}
 }
 
-func TestJREExtractedDir(t *testing.T) {
-	dir := JREExtractedDir()
-	if dir == "" {
-		t.Error("JREExtractedDir() returned empty string")
+func TestJREMarker(t *testing.T) {
+	installDir := t.TempDir()
+
+	got, err := ReadJREMarker(installDir)
+	if err != nil {
+		t.Fatalf("ReadJREMarker() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("ReadJREMarker() = %v, want empty before install", got)
 	}
 
-	// Should contain version
-	if !contains(dir, JREVersion) {
-		t.Errorf("JREExtractedDir() = %v, should contain version %v", dir, JREVersion)
+	if err := WriteJREMarker(installDir, "21.0.5+11"); err != nil {
+		t.Fatalf("WriteJREMarker() error = %v", err)
+	}
+
+	got, err = ReadJREMarker(installDir)
+	if err != nil {
+		t.Fatalf("ReadJREMarker() error = %v", err)
+	}
+	if got != "21.0.5+11" {
+		t.Errorf("ReadJREMarker() = %v, want 21.0.5+11", got)
+	}
+
+	if err := RemoveJREMarker(installDir); err != nil {
+		t.Fatalf("RemoveJREMarker() error = %v", err)
+	}
+	if err := RemoveJREMarker(installDir); err != nil {
+		t.Errorf("RemoveJREMarker() on missing marker error = %v", err)
 	}
 }
 
@@ -0,0 +1,200 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// ArgProblem is a complaint the JVM printed about its arguments.
+type ArgProblem struct {
+	Arg     string // Offending argument; empty if it couldn't be identified
+	Message string // The JVM's message
+	Fatal   bool   // The JVM refused to start
+}
+
+// jvmFatalPatterns match messages for arguments that stop the JVM. The
+// group, when present, names the option.
+var jvmFatalPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^Unrecognized option: (\S+)`),
+	regexp.MustCompile(`^Unrecognized VM option '([^']+)'`),
+	regexp.MustCompile(`^Improperly specified VM option '([^']+)'`),
+	regexp.MustCompile(`^Invalid (?:initial|maximum) heap size: (\S+)`),
+	regexp.MustCompile(`^Error: `),
+}
+
+// jvmNoise are lines printed alongside a real error that add nothing.
+var jvmNoise = []string{
+	"Error: Could not create the Java Virtual Machine.",
+	"Error: A fatal exception has occurred. Program will exit.",
+}
+
+// CheckJVMArgs starts the Java at javaPath with args and -version, and
+// returns every problem the JVM reports with them.
+func CheckJVMArgs(ctx context.Context, javaPath string, args []string) ([]ArgProblem, error) {
+	cmd := exec.CommandContext(ctx, javaPath, append(append([]string{}, args...), "-version")...)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("run %s: %w", javaPath, ctx.Err())
+	}
+
+	problems := parseArgProblems(output.String(), args)
+	if runErr != nil && !hasFatal(problems) {
+		problems = append(problems, ArgProblem{
+			Message: fmt.Sprintf("java exited with %v", runErr),
+			Fatal:   true,
+		})
+	}
+	return problems, nil
+}
+
+// parseArgProblems extracts errors and warnings from JVM output and
+// attributes each to the argument it mentions.
+func parseArgProblems(output string, args []string) []ArgProblem {
+	var problems []ArgProblem
+	seen := map[string]bool{}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] || isNoise(line) {
+			continue
+		}
+
+		problem, ok := parseArgLine(line)
+		if !ok {
+			continue
+		}
+		seen[line] = true
+
+		if problem.Arg == "" {
+			problem.Arg = attributeArg(problem.Message, args)
+		} else {
+			problem.Arg = matchArg(problem.Arg, args)
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+func parseArgLine(line string) (ArgProblem, bool) {
+	for _, re := range jvmFatalPatterns {
+		if m := re.FindStringSubmatch(line); m != nil {
+			p := ArgProblem{Message: line, Fatal: true}
+			if len(m) > 1 {
+				p.Arg = m[1]
+			}
+			return p, true
+		}
+	}
+
+	// "OpenJDK 64-Bit Server VM warning: ..." and "WARNING: ..."
+	if i := strings.Index(strings.ToLower(line), "warning:"); i >= 0 {
+		return ArgProblem{Message: strings.TrimSpace(line[i+len("warning:"):])}, true
+	}
+	return ArgProblem{}, false
+}
+
+func isNoise(line string) bool {
+	for _, n := range jvmNoise {
+		if line == n {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFatal(problems []ArgProblem) bool {
+	for _, p := range problems {
+		if p.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArg maps an option named by the JVM, such as "Foo" from
+// "Unrecognized VM option 'Foo'", to the argument that set it.
+func matchArg(name string, args []string) string {
+	for _, arg := range args {
+		if arg == name {
+			return arg
+		}
+	}
+	name = strings.TrimLeft(strings.TrimPrefix(name, "-XX:"), "+-")
+	for _, arg := range args {
+		if xx := optionName(arg); xx != "" && (xx == name || strings.HasPrefix(name, xx+"=")) {
+			return arg
+		}
+	}
+	return name
+}
+
+// optionName returns the bare name of an -XX option, e.g. "UseG1GC" for
+// "-XX:+UseG1GC" and "MaxRAM" for "-XX:MaxRAM=1g".
+func optionName(arg string) string {
+	xx, ok := strings.CutPrefix(arg, "-XX:")
+	if !ok {
+		return ""
+	}
+	xx, _, _ = strings.Cut(strings.TrimLeft(xx, "+-"), "=")
+	return xx
+}
+
+// attributeArg finds the argument a warning is about: one it quotes, an
+// -XX option it names, or for --add-opens and --add-exports, one naming
+// its module and package.
+func attributeArg(message string, args []string) string {
+	for _, arg := range args {
+		if strings.Contains(message, arg) {
+			return arg
+		}
+	}
+	for _, arg := range args {
+		if xx := optionName(arg); xx != "" && containsWord(message, xx) {
+			return arg
+		}
+	}
+
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || (name != "--add-opens" && name != "--add-exports") {
+			continue
+		}
+		target, _, _ := strings.Cut(value, "=")
+		module, pkg, _ := strings.Cut(target, "/")
+		if strings.Contains(message, target) ||
+			strings.Contains(message, "module: "+module+" ") ||
+			(strings.Contains(message, pkg) && strings.Contains(message, module)) {
+			return arg
+		}
+	}
+	return ""
+}
+
+// containsWord reports whether s contains word not as part of a longer
+// identifier.
+func containsWord(s, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isIdent(s[start-1])) && (end == len(s) || !isIdent(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
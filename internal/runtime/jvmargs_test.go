@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArgProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		args   []string
+		want   []ArgProblem
+	}{
+		{
+			name:   "clean",
+			output: "openjdk version \"21.0.5\" 2024-10-15\nOpenJDK Runtime Environment Temurin-21.0.5+11 (build 21.0.5+11-LTS)\n",
+			args:   []string{"-Xmx4g"},
+		},
+		{
+			name: "unrecognized option",
+			output: "Unrecognized option: -noverifyx\n" +
+				"Error: Could not create the Java Virtual Machine.\n" +
+				"Error: A fatal exception has occurred. Program will exit.\n",
+			args: []string{"-Xmx4g", "-noverifyx"},
+			want: []ArgProblem{{Arg: "-noverifyx", Message: "Unrecognized option: -noverifyx", Fatal: true}},
+		},
+		{
+			name: "unrecognized VM option",
+			output: "Unrecognized VM option 'UseConcMarkSweepGC'\n" +
+				"Error: Could not create the Java Virtual Machine.\n",
+			args: []string{"-XX:+UseConcMarkSweepGC"},
+			want: []ArgProblem{{Arg: "-XX:+UseConcMarkSweepGC", Message: "Unrecognized VM option 'UseConcMarkSweepGC'", Fatal: true}},
+		},
+		{
+			name:   "invalid heap",
+			output: "Invalid maximum heap size: -Xmx4q\nError: Could not create the Java Virtual Machine.\n",
+			args:   []string{"-Xmx4q"},
+			want:   []ArgProblem{{Arg: "-Xmx4q", Message: "Invalid maximum heap size: -Xmx4q", Fatal: true}},
+		},
+		{
+			name:   "deprecated option",
+			output: "OpenJDK 64-Bit Server VM warning: Options -Xverify:none and -noverify were deprecated in JDK 13 and will likely be removed in a future release.\nopenjdk version \"17.0.9\"\n",
+			args:   []string{"-noverify"},
+			want:   []ArgProblem{{Arg: "-noverify", Message: "Options -Xverify:none and -noverify were deprecated in JDK 13 and will likely be removed in a future release."}},
+		},
+		{
+			name:   "ignored option",
+			output: "OpenJDK 64-Bit Server VM warning: Ignoring option UseConcMarkSweepGC; support was removed in 14.0\n",
+			args:   []string{"-XX:+UseConcMarkSweepGC", "-XX:+UseG1GC"},
+			want:   []ArgProblem{{Arg: "-XX:+UseConcMarkSweepGC", Message: "Ignoring option UseConcMarkSweepGC; support was removed in 14.0"}},
+		},
+		{
+			name:   "unknown module",
+			output: "WARNING: Unknown module: java.desktopx specified to --add-opens\n",
+			args:   []string{"--add-opens=java.desktopx/javax.swing=ALL-UNNAMED"},
+			want:   []ArgProblem{{Arg: "--add-opens=java.desktopx/javax.swing=ALL-UNNAMED", Message: "Unknown module: java.desktopx specified to --add-opens"}},
+		},
+		{
+			name:   "unattributed warning",
+			output: "WARNING: something else happened\nWARNING: something else happened\n",
+			args:   []string{"-Xmx4g"},
+			want:   []ArgProblem{{Message: "something else happened"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseArgProblems(tt.output, tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseArgProblems() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// probeCacheFile is the probe cache file name inside the cache directory.
+const probeCacheFile = "java-probe.json"
+
+// ProbeCacheFile returns the probe cache location inside cacheDir.
+func ProbeCacheFile(cacheDir string) string {
+	return filepath.Join(cacheDir, probeCacheFile)
+}
+
+// Prober inspects java binaries, caching results per binary so repeated
+// launches don't pay for starting a JVM. A cached entry is reused only
+// while the binary's modification time and size are unchanged.
+type Prober struct {
+	CacheFile string                                  // JSON cache file; caching is disabled when empty
+	ProbeFunc func(path string) (Installation, error) // Default: ProbeJava
+}
+
+// probeEntry is a cached probe result.
+type probeEntry struct {
+	ModTime      time.Time    `json:"mod_time"`
+	Size         int64        `json:"size"`
+	Installation Installation `json:"installation"`
+}
+
+// Probe returns the properties of the java binary at path.
+func (p Prober) Probe(path string) (Installation, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Installation{}, fmt.Errorf("java not found at %s: %w", path, err)
+	}
+
+	cache := p.load()
+	if e, ok := cache[path]; ok && e.ModTime.Equal(info.ModTime()) && e.Size == info.Size() {
+		return e.Installation, nil
+	}
+
+	probe := p.ProbeFunc
+	if probe == nil {
+		probe = ProbeJava
+	}
+
+	inst, err := probe(path)
+	if err != nil {
+		return Installation{}, err
+	}
+
+	cache[path] = probeEntry{ModTime: info.ModTime(), Size: info.Size(), Installation: inst}
+	p.save(cache)
+
+	return inst, nil
+}
+
+// load reads the cache; a missing or corrupt cache is treated as empty.
+func (p Prober) load() map[string]probeEntry {
+	cache := map[string]probeEntry{}
+	if p.CacheFile == "" {
+		return cache
+	}
+
+	data, err := os.ReadFile(p.CacheFile)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return map[string]probeEntry{}
+	}
+	return cache
+}
+
+// save writes the cache best-effort; failures only cost a re-probe later.
+func (p Prober) save(cache map[string]probeEntry) {
+	if p.CacheFile == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.CacheFile), 0o755); err != nil {
+		return
+	}
+
+	tmp := p.CacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, p.CacheFile); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// VerifyJava probes the java binary at path and checks that it runs,
+// meets minVersion and matches the host architecture.
+func VerifyJava(p Prober, path string, minVersion int) (Installation, error) {
+	inst, err := p.Probe(path)
+	if err != nil {
+		return Installation{}, fmt.Errorf("java at %s failed to run: %w", path, err)
+	}
+
+	if inst.Major < minVersion {
+		return inst, fmt.Errorf("java at %s is version %d, but %d+ is required", path, inst.Major, minVersion)
+	}
+
+	if inst.Arch != "" && !HostArch(inst.Arch) {
+		return inst, fmt.Errorf("java at %s is built for %s, but this host is %s", path, inst.Arch, runtime.GOARCH)
+	}
+
+	return inst, nil
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProberCachesByModTime(t *testing.T) {
+	tmp := t.TempDir()
+	javaBin := fakeJavaHome(t, filepath.Join(tmp, "jdk"))
+
+	calls := 0
+	p := Prober{
+		CacheFile: ProbeCacheFile(filepath.Join(tmp, "cache")),
+		ProbeFunc: func(path string) (Installation, error) {
+			calls++
+			return Installation{Path: path, Major: 21, Version: "21.0.5"}, nil
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		inst, err := p.Probe(javaBin)
+		if err != nil {
+			t.Fatalf("Probe() error = %v", err)
+		}
+		if inst.Major != 21 {
+			t.Errorf("Probe() Major = %v, want 21", inst.Major)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("probe ran %d times, want 1 (second call should hit the cache)", calls)
+	}
+
+	// A fresh Prober reads the cache from disk
+	p2 := p
+	if _, err := p2.Probe(javaBin); err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("probe ran %d times, want 1 (cache should persist)", calls)
+	}
+
+	// Replacing the binary invalidates the entry
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(javaBin, later, later); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	if _, err := p.Probe(javaBin); err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("probe ran %d times, want 2 after binary changed", calls)
+	}
+}
+
+func TestResolveJavaPathUsesProbeCache(t *testing.T) {
+	tmp := t.TempDir()
+	javaHome := filepath.Join(tmp, "jdk")
+	javaBin := fakeJavaHome(t, javaHome)
+	t.Setenv("JAVA_HOME", javaHome)
+
+	probed := map[string]int{}
+	p := Prober{
+		CacheFile: ProbeCacheFile(filepath.Join(tmp, "cache")),
+		ProbeFunc: func(path string) (Installation, error) {
+			probed[path]++
+			if path != javaBin {
+				return Installation{}, os.ErrNotExist // Ignore the host's own Java
+			}
+			return Installation{Path: path, Major: 99, Version: "99.0.1"}, nil
+		},
+	}
+	opts := JavaOptions{Strategy: StrategySystem, MinVersion: 99, Prober: p}
+
+	for i := 0; i < 2; i++ {
+		got, err := ResolveJavaPath(opts)
+		if err != nil {
+			t.Fatalf("ResolveJavaPath() error = %v", err)
+		}
+		if got != javaBin {
+			t.Errorf("ResolveJavaPath() = %v, want %v", got, javaBin)
+		}
+	}
+	if probed[javaBin] != 1 {
+		t.Errorf("JAVA_HOME java probed %d times, want 1 (second resolve should hit the cache)", probed[javaBin])
+	}
+}
+
+func TestVerifyJava(t *testing.T) {
+	javaBin := fakeJavaHome(t, filepath.Join(t.TempDir(), "jdk"))
+
+	native := "amd64"
+	if runtime.GOARCH == "arm64" {
+		native = "aarch64"
+	}
+
+	tests := []struct {
+		name    string
+		inst    Installation
+		min     int
+		wantErr string
+	}{
+		{name: "valid", inst: Installation{Major: 21, Arch: native}, min: 17},
+		{name: "too old", inst: Installation{Major: 11, Arch: native}, min: 17, wantErr: "17+ is required"},
+		{name: "wrong architecture", inst: Installation{Major: 21, Arch: "s390x"}, min: 17, wantErr: "built for s390x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Prober{ProbeFunc: func(string) (Installation, error) { return tt.inst, nil }}
+
+			_, err := VerifyJava(p, javaBin, tt.min)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifyJava() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyJava() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyJavaBrokenBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script stand-in requires a POSIX shell")
+	}
+
+	javaBin := filepath.Join(t.TempDir(), "java")
+	if err := os.WriteFile(javaBin, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := VerifyJava(Prober{}, javaBin, 17); err == nil || !strings.Contains(err.Error(), "failed to run") {
+		t.Errorf("VerifyJava() error = %v, want failed to run", err)
+	}
+}
@@ -0,0 +1,276 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultJREMetadataURL is the Adoptium API used to look up Temurin releases.
+const DefaultJREMetadataURL = "https://api.adoptium.net"
+
+// JRESpec is a parsed runtime.java.bundled.version value.
+type JRESpec struct {
+	Feature int    // Feature version (e.g., 21); zero when Latest is set
+	Release string // Exact release name (e.g., "jdk-21.0.5+11"); empty unless pinned
+	Latest  bool   // Most recent LTS feature version
+}
+
+// Exact reports whether the spec pins a single release.
+func (s JRESpec) Exact() bool {
+	return s.Release != ""
+}
+
+// ParseJRESpec parses a bundled JRE version selector.
+// Accepts "latest", a feature version ("21") or an exact release
+// ("21.0.5+11" or "jdk-21.0.5+11"). An empty string selects DefaultJREFeature.
+func ParseJRESpec(s string) (JRESpec, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case s == "":
+		return JRESpec{Feature: DefaultJREFeature}, nil
+	case s == "latest":
+		return JRESpec{Latest: true}, nil
+	}
+
+	raw := strings.TrimPrefix(s, "jdk-")
+
+	if !strings.ContainsAny(raw, ".+") {
+		feature, err := strconv.Atoi(raw)
+		if err != nil || feature < 8 {
+			return JRESpec{}, fmt.Errorf("invalid JRE version: %s", s)
+		}
+		return JRESpec{Feature: feature}, nil
+	}
+
+	version, build, ok := strings.Cut(raw, "+")
+	if !ok || build == "" {
+		return JRESpec{}, fmt.Errorf("exact JRE release needs a build number (e.g., 21.0.5+11): %s", s)
+	}
+	if _, err := strconv.Atoi(build); err != nil {
+		return JRESpec{}, fmt.Errorf("invalid JRE build number: %s", s)
+	}
+
+	major, _, _ := strings.Cut(version, ".")
+	feature, err := strconv.Atoi(major)
+	if err != nil {
+		return JRESpec{}, fmt.Errorf("invalid JRE version: %s", s)
+	}
+
+	return JRESpec{Feature: feature, Release: "jdk-" + raw}, nil
+}
+
+// JRERelease describes a concrete Temurin JRE build for the current platform.
+type JRERelease struct {
+	Feature  int    // Feature version (e.g., 21)
+	Version  string // Semantic version (e.g., "21.0.5+11")
+	Release  string // Release name (e.g., "jdk-21.0.5+11")
+	URL      string // Archive download URL
+	FileName string // Archive file name
+	Checksum string // SHA-256 of the archive
+	Size     int64  // Archive size in bytes
+}
+
+// JREResolver looks up Temurin releases from an Adoptium-compatible
+// metadata endpoint.
+type JREResolver struct {
+	BaseURL string        // Metadata endpoint (default: DefaultJREMetadataURL)
+	Timeout time.Duration // Per-request timeout
+}
+
+// Resolve returns the JRE release matching spec for the current platform.
+func (r JREResolver) Resolve(ctx context.Context, spec string) (JRERelease, error) {
+	parsed, err := ParseJRESpec(spec)
+	if err != nil {
+		return JRERelease{}, err
+	}
+
+	osName, archName, err := adoptiumPlatform()
+	if err != nil {
+		return JRERelease{}, err
+	}
+
+	if parsed.Latest {
+		feature, err := r.latestLTS(ctx)
+		if err != nil {
+			return JRERelease{}, err
+		}
+		parsed.Feature = feature
+	}
+
+	query := url.Values{
+		"architecture": {archName},
+		"image_type":   {"jre"},
+		"os":           {osName},
+	}
+
+	if parsed.Exact() {
+		return r.resolveRelease(ctx, parsed, query)
+	}
+	return r.resolveFeature(ctx, parsed, query)
+}
+
+// adoptiumAsset mirrors the binary entries returned by the Adoptium API.
+type adoptiumAsset struct {
+	Binary      adoptiumBinary  `json:"binary"`
+	ReleaseName string          `json:"release_name"`
+	Version     adoptiumVersion `json:"version"`
+}
+
+type adoptiumBinary struct {
+	ImageType string `json:"image_type"`
+	Package   struct {
+		Name     string `json:"name"`
+		Link     string `json:"link"`
+		Checksum string `json:"checksum"`
+		Size     int64  `json:"size"`
+	} `json:"package"`
+}
+
+type adoptiumVersion struct {
+	Major  int    `json:"major"`
+	Semver string `json:"semver"`
+}
+
+// resolveFeature returns the newest release of a feature version.
+func (r JREResolver) resolveFeature(ctx context.Context, spec JRESpec, query url.Values) (JRERelease, error) {
+	query.Set("vendor", "eclipse")
+	endpoint := fmt.Sprintf("/v3/assets/latest/%d/hotspot?%s", spec.Feature, query.Encode())
+
+	var assets []adoptiumAsset
+	if err := r.getJSON(ctx, endpoint, &assets); err != nil {
+		return JRERelease{}, err
+	}
+
+	for _, a := range assets {
+		if a.Binary.ImageType == "jre" {
+			return toRelease(a.ReleaseName, a.Version, a.Binary), nil
+		}
+	}
+
+	return JRERelease{}, fmt.Errorf("no Temurin %d JRE published for this platform", spec.Feature)
+}
+
+// resolveRelease returns a single pinned release.
+func (r JREResolver) resolveRelease(ctx context.Context, spec JRESpec, query url.Values) (JRERelease, error) {
+	query.Set("project", "jdk")
+	endpoint := fmt.Sprintf("/v3/assets/release_name/eclipse/%s?%s", url.PathEscape(spec.Release), query.Encode())
+
+	var release struct {
+		Binaries    []adoptiumBinary `json:"binaries"`
+		ReleaseName string           `json:"release_name"`
+		VersionData adoptiumVersion  `json:"version_data"`
+	}
+	if err := r.getJSON(ctx, endpoint, &release); err != nil {
+		return JRERelease{}, err
+	}
+
+	for _, b := range release.Binaries {
+		if b.ImageType == "jre" {
+			return toRelease(release.ReleaseName, release.VersionData, b), nil
+		}
+	}
+
+	return JRERelease{}, fmt.Errorf("release %s has no JRE for this platform", spec.Release)
+}
+
+// latestLTS returns the most recent LTS feature version.
+func (r JREResolver) latestLTS(ctx context.Context) (int, error) {
+	var info struct {
+		MostRecentLTS int `json:"most_recent_lts"`
+	}
+	if err := r.getJSON(ctx, "/v3/info/available_releases", &info); err != nil {
+		return 0, err
+	}
+	if info.MostRecentLTS == 0 {
+		return 0, fmt.Errorf("release metadata did not report an LTS version")
+	}
+	return info.MostRecentLTS, nil
+}
+
+func (r JREResolver) getJSON(ctx context.Context, endpoint string, v any) error {
+	base := r.BaseURL
+	if base == "" {
+		base = DefaultJREMetadataURL
+	}
+
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(base, "/")+endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("query release metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("JRE release not found (%s)", endpoint)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("release metadata: unexpected status: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode release metadata: %w", err)
+	}
+	return nil
+}
+
+func toRelease(name string, v adoptiumVersion, b adoptiumBinary) JRERelease {
+	version := v.Semver
+	if version == "" {
+		version = strings.TrimPrefix(name, "jdk-")
+	}
+
+	return JRERelease{
+		Feature:  v.Major,
+		Version:  version,
+		Release:  name,
+		URL:      b.Package.Link,
+		FileName: b.Package.Name,
+		Checksum: b.Package.Checksum,
+		Size:     b.Package.Size,
+	}
+}
+
+// adoptiumPlatform returns the Adoptium os and architecture identifiers
+// for the current platform.
+func adoptiumPlatform() (osName, archName string, err error) {
+	switch runtime.GOOS {
+	case "linux":
+		osName = "linux"
+	case "darwin":
+		osName = "mac"
+	case "windows":
+		osName = "windows"
+	default:
+		return "", "", fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	}
+
+	switch runtime.GOARCH {
+	case "amd64":
+		archName = "x64"
+	case "arm64":
+		archName = "aarch64"
+	default:
+		return "", "", fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+	}
+
+	return osName, archName, nil
+}
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestParseJRESpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    JRESpec
+		wantErr bool
+	}{
+		{name: "empty uses default", input: "", want: JRESpec{Feature: DefaultJREFeature}},
+		{name: "latest", input: "latest", want: JRESpec{Latest: true}},
+		{name: "feature version", input: "17", want: JRESpec{Feature: 17}},
+		{name: "exact release", input: "21.0.5+11", want: JRESpec{Feature: 21, Release: "jdk-21.0.5+11"}},
+		{name: "exact release with prefix", input: "jdk-21.0.5+11", want: JRESpec{Feature: 21, Release: "jdk-21.0.5+11"}},
+		{name: "GA release", input: "21+35", want: JRESpec{Feature: 21, Release: "jdk-21+35"}},
+		{name: "missing build number", input: "21.0.5", wantErr: true},
+		{name: "feature too old", input: "7", wantErr: true},
+		{name: "garbage", input: "twenty-one", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJRESpec(tt.input)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseJRESpec() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseJRESpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newMetadataServer stands in for the Adoptium API.
+func newMetadataServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	osName, archName, err := adoptiumPlatform()
+	if err != nil {
+		t.Skipf("unsupported platform: %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/info/available_releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"most_recent_lts": 21, "most_recent_feature_release": 23}`))
+	})
+	mux.HandleFunc("/v3/assets/latest/21/hotspot", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("os") != osName || q.Get("architecture") != archName || q.Get("image_type") != "jre" {
+			http.Error(w, "unexpected query: "+r.URL.RawQuery, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[{
+			"binary": {"image_type": "jre", "package": {
+				"name": "OpenJDK21U-jre_x64_linux_hotspot_21.0.6_7.tar.gz",
+				"link": "https://example.com/21.0.6_7.tar.gz",
+				"checksum": "deadbeef",
+				"size": 52000000
+			}},
+			"release_name": "jdk-21.0.6+7",
+			"version": {"major": 21, "semver": "21.0.6+7"}
+		}]`))
+	})
+	mux.HandleFunc("/v3/assets/release_name/eclipse/jdk-21.0.5+11", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"binaries": [
+				{"image_type": "jdk", "package": {"name": "jdk.tar.gz", "link": "https://example.com/jdk.tar.gz"}},
+				{"image_type": "jre", "package": {"name": "jre.tar.gz", "link": "https://example.com/jre.tar.gz", "checksum": "cafe"}}
+			],
+			"release_name": "jdk-21.0.5+11",
+			"version_data": {"major": 21, "semver": "21.0.5+11"}
+		}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestJREResolverResolve(t *testing.T) {
+	srv := newMetadataServer(t)
+	r := JREResolver{BaseURL: srv.URL}
+
+	tests := []struct {
+		name        string
+		spec        string
+		wantVersion string
+		wantURL     string
+		wantErr     bool
+	}{
+		{name: "feature version", spec: "21", wantVersion: "21.0.6+7", wantURL: "https://example.com/21.0.6_7.tar.gz"},
+		{name: "latest LTS", spec: "latest", wantVersion: "21.0.6+7", wantURL: "https://example.com/21.0.6_7.tar.gz"},
+		{name: "exact release", spec: "21.0.5+11", wantVersion: "21.0.5+11", wantURL: "https://example.com/jre.tar.gz"},
+		{name: "unknown release", spec: "21.0.4+7", wantErr: true},
+		{name: "unknown feature", spec: "17", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Resolve(context.Background(), tt.spec)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Version != tt.wantVersion {
+				t.Errorf("Resolve() Version = %v, want %v", got.Version, tt.wantVersion)
+			}
+			if got.URL != tt.wantURL {
+				t.Errorf("Resolve() URL = %v, want %v", got.URL, tt.wantURL)
+			}
+			if got.Feature != 21 {
+				t.Errorf("Resolve() Feature = %v, want 21", got.Feature)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DiskFree returns the bytes available to the current user on the
+// filesystem holding path. A path that doesn't exist yet is measured at
+// its nearest existing parent, where it would be created.
+func DiskFree(path string) (uint64, error) {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	return diskFree(path)
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package sysinfo
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func diskFree(path string) (uint64, error) {
+	return 0, fmt.Errorf("disk space detection not supported on %s", runtime.GOOS)
+}
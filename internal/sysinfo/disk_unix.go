@@ -0,0 +1,16 @@
+//go:build linux || darwin
+
+package sysinfo
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func diskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,30 @@
+package sysinfo
+
+import (
+	"os"
+	"runtime"
+)
+
+// Display reports whether a graphical session is available and how it was
+// detected. On Linux and other Unix systems that means an X11 or Wayland
+// display; macOS and Windows sessions always have one, except macOS over
+// SSH, which cannot reach the window server.
+func Display() (available bool, how string) {
+	switch runtime.GOOS {
+	case "windows":
+		return true, "Windows desktop"
+	case "darwin":
+		if os.Getenv("SSH_CONNECTION") != "" {
+			return false, "SSH session"
+		}
+		return true, "macOS window server"
+	}
+
+	if d := os.Getenv("WAYLAND_DISPLAY"); d != "" {
+		return true, "Wayland " + d
+	}
+	if d := os.Getenv("DISPLAY"); d != "" {
+		return true, "X11 " + d
+	}
+	return false, "DISPLAY and WAYLAND_DISPLAY not set"
+}
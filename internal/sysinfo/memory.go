@@ -0,0 +1,88 @@
+// Package sysinfo reports host resources relevant to running Burp Suite.
+package sysinfo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MemoryInfo describes memory available to processes started by relay.
+type MemoryInfo struct {
+	Total     uint64 // Physical memory in bytes
+	Limit     uint64 // Effective limit: the cgroup limit when lower than Total, otherwise Total
+	Available uint64 // Memory currently available for new processes; 0 if unknown
+}
+
+// Memory returns the host memory information.
+func Memory() (MemoryInfo, error) {
+	info, err := readMemory()
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	if info.Limit == 0 || info.Limit > info.Total {
+		info.Limit = info.Total
+	}
+	if info.Available > info.Limit {
+		info.Available = info.Limit
+	}
+
+	return info, nil
+}
+
+// parseMeminfo extracts MemTotal and MemAvailable (in bytes) from /proc/meminfo.
+func parseMeminfo(data string) (total, available uint64) {
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch key {
+		case "MemTotal":
+			total = kb * 1024
+		case "MemAvailable":
+			available = kb * 1024
+		}
+	}
+	return total, available
+}
+
+// parseCgroupLimit parses a cgroup memory limit file.
+// Returns 0 for "max" (cgroup v2) or unlimited sentinel values (cgroup v1).
+func parseCgroupLimit(data string) uint64 {
+	data = strings.TrimSpace(data)
+	if data == "" || data == "max" {
+		return 0
+	}
+
+	limit, err := strconv.ParseUint(data, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	// cgroup v1 reports "unlimited" as a page-aligned value near MaxInt64
+	if limit >= 1<<62 {
+		return 0
+	}
+	return limit
+}
+
+// cgroupV2Path extracts the unified hierarchy path from /proc/self/cgroup.
+func cgroupV2Path(data string) string {
+	for _, line := range strings.Split(data, "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
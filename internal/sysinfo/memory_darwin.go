@@ -0,0 +1,25 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func readMemory() (MemoryInfo, error) {
+	out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return MemoryInfo{}, fmt.Errorf("sysctl hw.memsize: %w", err)
+	}
+
+	total, err := strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return MemoryInfo{}, fmt.Errorf("parse hw.memsize: %w", err)
+	}
+
+	// macOS has no cheap equivalent of MemAvailable; leave it unknown
+	return MemoryInfo{Total: total}, nil
+}
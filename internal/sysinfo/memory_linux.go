@@ -0,0 +1,46 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func readMemory() (MemoryInfo, error) {
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return MemoryInfo{}, fmt.Errorf("read meminfo: %w", err)
+	}
+
+	total, available := parseMeminfo(string(data))
+	if total == 0 {
+		return MemoryInfo{}, fmt.Errorf("MemTotal missing from /proc/meminfo")
+	}
+
+	return MemoryInfo{
+		Total:     total,
+		Limit:     cgroupLimit(),
+		Available: available,
+	}, nil
+}
+
+// cgroupLimit returns the memory limit of the current cgroup, or 0 if none.
+func cgroupLimit() uint64 {
+	// cgroup v2: limit lives in the process's own cgroup directory
+	if data, err := os.ReadFile("/proc/self/cgroup"); err == nil {
+		if path := cgroupV2Path(string(data)); path != "" {
+			if limit, err := os.ReadFile(filepath.Join("/sys/fs/cgroup", path, "memory.max")); err == nil {
+				return parseCgroupLimit(string(limit))
+			}
+		}
+	}
+
+	// cgroup v1
+	if limit, err := os.ReadFile("/sys/fs/cgroup/memory/memory.limit_in_bytes"); err == nil {
+		return parseCgroupLimit(string(limit))
+	}
+
+	return 0
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package sysinfo
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func readMemory() (MemoryInfo, error) {
+	return MemoryInfo{}, fmt.Errorf("memory detection not supported on %s", runtime.GOOS)
+}
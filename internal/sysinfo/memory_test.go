@@ -0,0 +1,51 @@
+package sysinfo
+
+import "testing"
+
+func TestParseMeminfo(t *testing.T) {
+	data := `MemTotal:       16318024 kB
+MemFree:         1021396 kB
+MemAvailable:    9215460 kB
+Buffers:          512000 kB
+`
+
+	total, available := parseMeminfo(data)
+	if total != 16318024*1024 {
+		t.Errorf("parseMeminfo() total = %v, want %v", total, 16318024*1024)
+	}
+	if available != 9215460*1024 {
+		t.Errorf("parseMeminfo() available = %v, want %v", available, 9215460*1024)
+	}
+}
+
+func TestParseCgroupLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want uint64
+	}{
+		{name: "cgroup v2 unlimited", data: "max\n", want: 0},
+		{name: "cgroup v2 limit", data: "4294967296\n", want: 4294967296},
+		{name: "cgroup v1 unlimited", data: "9223372036854771712\n", want: 0},
+		{name: "empty", data: "", want: 0},
+		{name: "garbage", data: "lots", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCgroupLimit(tt.data); got != tt.want {
+				t.Errorf("parseCgroupLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCgroupV2Path(t *testing.T) {
+	data := "12:memory:/legacy\n0::/user.slice/user-1000.slice/session-2.scope\n"
+	if got := cgroupV2Path(data); got != "/user.slice/user-1000.slice/session-2.scope" {
+		t.Errorf("cgroupV2Path() = %v", got)
+	}
+	if got := cgroupV2Path("12:memory:/legacy\n"); got != "" {
+		t.Errorf("cgroupV2Path() = %v, want empty for cgroup v1 only", got)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// memoryStatusEx mirrors the Win32 MEMORYSTATUSEX structure.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+var procGlobalMemoryStatusEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GlobalMemoryStatusEx")
+
+func readMemory() (MemoryInfo, error) {
+	var status memoryStatusEx
+	status.Length = uint32(unsafe.Sizeof(status))
+
+	ret, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return MemoryInfo{}, fmt.Errorf("GlobalMemoryStatusEx: %w", err)
+	}
+
+	return MemoryInfo{
+		Total:     status.TotalPhys,
+		Available: status.AvailPhys,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package sysinfo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Process identifies a running process.
+type Process struct {
+	PID  int
+	Name string // Executable name; empty if unknown
+}
+
+// PortOwner returns the process listening on a local TCP port. It fails
+// when the OS doesn't say, e.g. for another user's process without root.
+func PortOwner(port int) (Process, error) {
+	return portOwner(port)
+}
+
+// parseProcNetTCP returns the socket inodes listening on port in the
+// contents of /proc/net/tcp or /proc/net/tcp6.
+func parseProcNetTCP(data string, port int) []string {
+	var inodes []string
+	for _, line := range strings.Split(data, "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 10 || fields[3] != "0A" { // 0A is LISTEN
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		if p, err := strconv.ParseUint(hexPort, 16, 16); err == nil && int(p) == port {
+			inodes = append(inodes, fields[9])
+		}
+	}
+	return inodes
+}
+
+// parseLsof reads the process from lsof -F pc output.
+func parseLsof(out string) (Process, bool) {
+	var proc Process
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "p") && proc.PID == 0:
+			proc.PID, _ = strconv.Atoi(line[1:])
+		case strings.HasPrefix(line, "c") && proc.Name == "":
+			proc.Name = line[1:]
+		}
+	}
+	return proc, proc.PID != 0
+}
+
+// parseNetstat finds the PID listening on port in netstat -ano output.
+func parseNetstat(out string, port int) (int, bool) {
+	suffix := ":" + strconv.Itoa(port)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[0] != "TCP" || fields[3] != "LISTENING" {
+			continue
+		}
+		if strings.HasSuffix(fields[1], suffix) {
+			if pid, err := strconv.Atoi(fields[4]); err == nil {
+				return pid, true
+			}
+		}
+	}
+	return 0, false
+}
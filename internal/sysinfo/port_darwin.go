@@ -0,0 +1,21 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+func portOwner(port int) (Process, error) {
+	out, err := exec.Command("lsof", "-nP", "-iTCP:"+strconv.Itoa(port), "-sTCP:LISTEN", "-Fpc").Output()
+	if err != nil {
+		return Process{}, fmt.Errorf("lsof: %w", err)
+	}
+	proc, ok := parseLsof(string(out))
+	if !ok {
+		return Process{}, fmt.Errorf("no listener on port %d in lsof output", port)
+	}
+	return proc, nil
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func portOwner(port int) (Process, error) {
+	want := map[string]bool{}
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, inode := range parseProcNetTCP(string(data), port) {
+			want["socket:["+inode+"]"] = true
+		}
+	}
+	if len(want) == 0 {
+		return Process{}, fmt.Errorf("no listener on port %d in /proc/net", port)
+	}
+
+	// Only processes we may inspect show their file descriptors
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range fds {
+		if link, err := os.Readlink(fd); err != nil || !want[link] {
+			continue
+		}
+		pidDir := filepath.Dir(filepath.Dir(fd))
+		pid, _ := strconv.Atoi(filepath.Base(pidDir))
+		comm, _ := os.ReadFile(filepath.Join(pidDir, "comm"))
+		return Process{PID: pid, Name: strings.TrimSpace(string(comm))}, nil
+	}
+	return Process{}, fmt.Errorf("port %d belongs to a process of another user", port)
+}
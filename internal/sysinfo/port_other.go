@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package sysinfo
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func portOwner(port int) (Process, error) {
+	return Process{}, fmt.Errorf("port owner lookup not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,46 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProcNetTCP(t *testing.T) {
+	data := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41234 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:C350 01 00000000:00000000 00:00000000 00000000  1000        0 41299 1 0000000000000000 20 4 30 10 -1
+   2: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1822 1 0000000000000000 100 0 0 10 0
+`
+
+	if got := parseProcNetTCP(data, 8080); !reflect.DeepEqual(got, []string{"41234"}) {
+		t.Errorf("parseProcNetTCP(8080) = %v, want [41234]", got)
+	}
+	if got := parseProcNetTCP(data, 9090); got != nil {
+		t.Errorf("parseProcNetTCP(9090) = %v, want none", got)
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	got, ok := parseLsof("p4242\ncjava\nf12\n")
+	if !ok || got != (Process{PID: 4242, Name: "java"}) {
+		t.Errorf("parseLsof() = %+v, %v; want java 4242", got, ok)
+	}
+}
+
+func TestParseNetstat(t *testing.T) {
+	out := `
+Active Connections
+
+  Proto  Local Address          Foreign Address        State           PID
+  TCP    0.0.0.0:135            0.0.0.0:0              LISTENING       1020
+  TCP    127.0.0.1:8080         0.0.0.0:0              LISTENING       5120
+  TCP    127.0.0.1:8080         127.0.0.1:50123        ESTABLISHED     5120
+`
+
+	if pid, ok := parseNetstat(out, 8080); !ok || pid != 5120 {
+		t.Errorf("parseNetstat(8080) = %d, %v; want 5120", pid, ok)
+	}
+	if _, ok := parseNetstat(out, 80); ok {
+		t.Error("parseNetstat(80) found a listener, want none")
+	}
+}
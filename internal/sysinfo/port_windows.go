@@ -0,0 +1,31 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func portOwner(port int) (Process, error) {
+	out, err := exec.Command("netstat", "-ano", "-p", "TCP").Output()
+	if err != nil {
+		return Process{}, fmt.Errorf("netstat: %w", err)
+	}
+	pid, ok := parseNetstat(string(out), port)
+	if !ok {
+		return Process{}, fmt.Errorf("no listener on port %d in netstat output", port)
+	}
+
+	proc := Process{PID: pid}
+	out, err = exec.Command("tasklist", "/FI", "PID eq "+strconv.Itoa(pid), "/FO", "CSV", "/NH").Output()
+	if err == nil {
+		if rec, err := csv.NewReader(strings.NewReader(string(out))).Read(); err == nil && len(rec) > 0 {
+			proc.Name = rec[0]
+		}
+	}
+	return proc, nil
+}
@@ -7,6 +7,7 @@ type LayoutMode string
 const (
 	SystemLayout   LayoutMode = "system"
 	PortableLayout LayoutMode = "portable"
+	SharedLayout   LayoutMode = "shared" // Admin-managed install, per-user data
 )
 
 type JavaStrategy string
@@ -15,6 +16,8 @@ const (
 	JavaStrategyAuto    JavaStrategy = "auto"    // Prefer bundled, fallback to system
 	JavaStrategySystem  JavaStrategy = "system"  // Only use system Java
 	JavaStrategyBundled JavaStrategy = "bundled" // Only use bundled JRE
+	JavaStrategyPath    JavaStrategy = "path"    // Only use the Java in java_home
+	JavaStrategyManaged JavaStrategy = "managed" // relay installs and pins a bundled JRE
 )
 
 type LogLevel string
@@ -25,13 +28,35 @@ const (
 	LogLevelTrace LogLevel = "trace"
 )
 
+// CurrentVersion is the config_version this release reads and writes.
+// Older files are upgraded on load; see migrations.
+const CurrentVersion = 2
+
 type Config struct {
+	ConfigVersion int           `yaml:"config_version"` // Schema version; files without it are version 1
+	Product       ProductConfig `yaml:"product"`
+	Layout        LayoutConfig  `yaml:"layout"`
+	Paths         PathsConfig   `yaml:"paths"`
+	Runtime       RuntimeConfig `yaml:"runtime"`
+	Desktop       DesktopConfig `yaml:"desktop"`
+	Burp          BurpConfig    `yaml:"burp"`
+	Backup        BackupConfig  `yaml:"backup"`
+	Network       NetworkConfig `yaml:"network"`
+	Logging       LoggingConfig `yaml:"logging"`
+
+	Installs    []InstallConfig `yaml:"installs,omitempty"` // Named installs; see Select
+	InstallName string          `yaml:"-"`                  // Install the settings were selected for; empty without installs
+}
+
+// InstallConfig declares a named install. Settings it leaves unset are
+// inherited from the top level.
+type InstallConfig struct {
+	Name    string        `yaml:"name"`
 	Product ProductConfig `yaml:"product"`
 	Layout  LayoutConfig  `yaml:"layout"`
 	Paths   PathsConfig   `yaml:"paths"`
 	Runtime RuntimeConfig `yaml:"runtime"`
-	Network NetworkConfig `yaml:"network"`
-	Logging LoggingConfig `yaml:"logging"`
+	Desktop DesktopConfig `yaml:"desktop"`
 }
 
 type ProductConfig struct {
@@ -41,7 +66,8 @@ type ProductConfig struct {
 }
 
 type LayoutConfig struct {
-	Mode LayoutMode `yaml:"mode"`
+	Mode  LayoutMode `yaml:"mode"`
+	Group string     `yaml:"group"` // Group given access to a shared install
 }
 
 type PathsConfig struct {
@@ -55,9 +81,34 @@ type RuntimeConfig struct {
 }
 
 type JavaConfig struct {
-	Strategy   JavaStrategy `yaml:"strategy"`
-	MinVersion int          `yaml:"min_version"`
-	JVMArgs    []string     `yaml:"jvm_args"`
+	Strategy   JavaStrategy      `yaml:"strategy"`
+	JavaHome   string            `yaml:"java_home"` // Required by the path strategy
+	MinVersion int               `yaml:"min_version"`
+	Heap       string            `yaml:"heap"` // Max heap: "auto", a percentage of memory ("50%") or a size ("4g")
+	JVMArgs    []string          `yaml:"jvm_args"`
+	Bundled    BundledJavaConfig `yaml:"bundled"`
+}
+
+// BundledJavaConfig selects the JRE relay downloads when one is needed.
+type BundledJavaConfig struct {
+	Version  string `yaml:"version"`  // Feature version ("21"), exact release ("21.0.5+11") or "latest"
+	Metadata string `yaml:"metadata"` // Release metadata endpoint (Adoptium API)
+}
+
+// DesktopConfig controls the desktop integration relay manages.
+type DesktopConfig struct {
+	Shortcut bool `yaml:"shortcut"` // Add the launcher to the desktop's application menu
+}
+
+// BurpConfig holds Burp Suite settings relay checks against the host.
+type BurpConfig struct {
+	ProxyListeners []string `yaml:"proxy_listeners"` // host:port; empty reads Burp's own options
+}
+
+// BackupConfig controls archives written by relay backup.
+type BackupConfig struct {
+	Auto bool   `yaml:"auto"` // Back up before update and remove
+	Dir  string `yaml:"dir"`  // Archive directory; "auto" is backups/ in the config directory
 }
 
 type NetworkConfig struct {
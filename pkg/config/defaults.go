@@ -1,9 +1,16 @@
 package config
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// heapKey is the maximum heap setting, which replaces -Xmx in jvm_args.
+const heapKey = "runtime.java.heap"
 
 func Default() Config {
 	return Config{
+		ConfigVersion: CurrentVersion,
 		Product: ProductConfig{
 			Name:    "burpsuite",
 			Edition: "professional",
@@ -21,9 +28,9 @@ func Default() Config {
 			Java: JavaConfig{
 				Strategy:   JavaStrategyAuto,
 				MinVersion: 17,
+				// Sized from host memory at launch time
+				Heap: "auto",
 				JVMArgs: []string{
-					// Memory settings
-					"-Xmx4g",
 					"-Xms1g",
 					// Modern G1 garbage collector (default in JDK 17+, explicit for clarity)
 					"-XX:+UseG1GC",
@@ -40,8 +47,16 @@ func Default() Config {
 					"--add-opens=java.base/java.util.concurrent=ALL-UNNAMED",
 					"--add-opens=java.base/jdk.internal.misc=ALL-UNNAMED",
 				},
+				Bundled: BundledJavaConfig{
+					Version:  "21",
+					Metadata: "https://api.adoptium.net",
+				},
 			},
 		},
+		Backup: BackupConfig{
+			Auto: false,
+			Dir:  "auto",
+		},
 		Network: NetworkConfig{
 			Timeout: 30 * time.Second,
 			Retries: 3,
@@ -51,3 +66,14 @@ func Default() Config {
 		},
 	}
 }
+
+// dropDefaultHeap clears a runtime.java.heap left at its default when
+// jvm_args sets -Xmx, so the explicit -Xmx isn't replaced.
+func (c *Config) dropDefaultHeap() {
+	for _, arg := range c.Runtime.Java.JVMArgs {
+		if strings.HasPrefix(arg, "-Xmx") {
+			c.Runtime.Java.Heap = ""
+			return
+		}
+	}
+}
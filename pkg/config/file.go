@@ -0,0 +1,341 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is a config file opened for editing. Comments and key order
+// survive a Set/Unset/Write round trip.
+type File struct {
+	Path string
+	doc  *yaml.Node // Top-level mapping
+}
+
+// PositionError is an error located at a line and column of a config file.
+type PositionError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *PositionError) Error() string {
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %v", e.Path, e.Line, e.Column, e.Err)
+	}
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
+// atPath attributes err to the config file at path.
+func atPath(path string, err error) error {
+	var pe *PositionError
+	if errors.As(err, &pe) {
+		pe.Path = path
+		return pe
+	}
+	return &PositionError{Path: path, Err: err}
+}
+
+// yamlLinePattern matches the "line N: " yaml.v3 puts in parse errors.
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError converts a yaml.v3 parse error into a PositionError.
+func yamlError(path string, err error) error {
+	m := yamlLinePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return &PositionError{Path: path, Err: err}
+	}
+	line, _ := strconv.Atoi(m[1])
+	return &PositionError{Path: path, Line: line, Err: errors.New(m[2])}
+}
+
+// yamlErrors converts a yaml.v3 decode error, which may hold one message
+// per mismatched value, into PositionErrors.
+func yamlErrors(path string, err error) []error {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return []error{yamlError(path, err)}
+	}
+
+	errs := make([]error, len(te.Errors))
+	for i, msg := range te.Errors {
+		errs[i] = yamlError(path, errors.New(msg))
+	}
+	return errs
+}
+
+// OpenFile parses the config file at path. A missing file opens empty.
+func OpenFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ParseFile(path, nil)
+		}
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return ParseFile(path, data)
+}
+
+// ParseFile parses data as the contents of the config file at path.
+func ParseFile(path string, data []byte) (*File, error) {
+	f := &File{Path: path, doc: &yaml.Node{Kind: yaml.MappingNode}}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlError(path, err)
+	}
+	if len(doc.Content) == 0 {
+		return f, nil // Empty file
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, &PositionError{Path: path, Line: doc.Content[0].Line, Column: doc.Content[0].Column, Err: errors.New("config must be a mapping")}
+	}
+
+	f.doc = doc.Content[0]
+	return f, nil
+}
+
+// Config returns Default() overlaid with the settings in the file,
+// upgraded to CurrentVersion. Unknown keys and mistyped values are errors.
+func (f *File) Config() (Config, error) {
+	doc := cloneNode(f.doc)
+	if _, err := Migrate(doc); err != nil {
+		return Config{}, atPath(f.Path, err)
+	}
+
+	cfg := Default()
+	if err := decodeStrict(f.Path, doc, &cfg); err != nil {
+		return Config{}, err
+	}
+	if _, ok := nodeKeys(doc, "")[heapKey]; !ok {
+		cfg.dropDefaultHeap()
+	}
+	return cfg, nil
+}
+
+// Version returns the file's config_version.
+func (f *File) Version() (int, error) {
+	v, err := DocumentVersion(f.doc)
+	if err != nil {
+		return 0, atPath(f.Path, err)
+	}
+	return v, nil
+}
+
+// Migrate upgrades the file to CurrentVersion and returns the migrations
+// applied. Call Write to save the result.
+func (f *File) Migrate() ([]Migration, error) {
+	applied, err := Migrate(f.doc)
+	if err != nil {
+		return nil, atPath(f.Path, err)
+	}
+	return applied, nil
+}
+
+// Validate decodes the file over the defaults and validates the result,
+// returning every problem. Errors for a setting point at its line and
+// column when the file sets it.
+func (f *File) Validate() error {
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.Validate()
+	if err == nil {
+		return nil
+	}
+
+	var list Errors
+	if !errors.As(err, &list) {
+		return atPath(f.Path, err)
+	}
+
+	var errs []error
+	for _, e := range list {
+		pe := &PositionError{Path: f.Path, Err: e}
+		if fe, ok := e.(*FieldError); ok {
+			if n := f.lookup(fe.Key); n != nil {
+				pe.Line, pe.Column = n.Line, n.Column
+			}
+		}
+		errs = append(errs, pe)
+	}
+	return errorList(errs)
+}
+
+// Get returns the value of key as written in the file.
+func (f *File) Get(key string) (string, bool) {
+	n := f.lookup(key)
+	if n == nil {
+		return "", false
+	}
+
+	if n.Kind == yaml.SequenceNode {
+		items := make([]string, len(n.Content))
+		for i, item := range n.Content {
+			items[i] = item.Value
+		}
+		return strings.Join(items, ","), true
+	}
+	return n.Value, true
+}
+
+// Set parses value as the type of key and writes it into the file.
+func (f *File) Set(key, value string) error {
+	cfg := Default()
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+
+	v, err := field(reflect.ValueOf(&cfg).Elem(), key)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := node.Encode(v.Interface()); err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+
+	parts := strings.Split(key, ".")
+	m := f.doc
+	for _, part := range parts[:len(parts)-1] {
+		child := mappingValue(m, part)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(m, part, child)
+		}
+		m = child
+	}
+
+	if old := mappingValue(m, parts[len(parts)-1]); old != nil {
+		node.HeadComment, node.LineComment, node.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	}
+	setMappingValue(m, parts[len(parts)-1], &node)
+
+	return nil
+}
+
+// Unset removes key from the file so lower layers apply again, and
+// reports whether it was set. Sections left empty are removed too.
+func (f *File) Unset(key string) (bool, error) {
+	if !IsKey(key) {
+		return false, fmt.Errorf("unknown config key: %s", key)
+	}
+	return unset(f.doc, strings.Split(key, ".")), nil
+}
+
+func unset(m *yaml.Node, parts []string) bool {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != parts[0] {
+			continue
+		}
+
+		if len(parts) > 1 {
+			child := m.Content[i+1]
+			if child.Kind != yaml.MappingNode || !unset(child, parts[1:]) {
+				return false
+			}
+			if len(child.Content) > 0 {
+				return true
+			}
+		}
+
+		m.Content = append(m.Content[:i], m.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+// Write saves the file, creating its directory if needed.
+func (f *File) Write() error {
+	data, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := os.Rename(tmp, f.Path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	return nil
+}
+
+// Bytes encodes the file as YAML.
+func (f *File) Bytes() ([]byte, error) {
+	if len(f.doc.Content) == 0 && f.doc.HeadComment == "" {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f.doc); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// lookup returns the value node for key, or nil if it is not set.
+func (f *File) lookup(key string) *yaml.Node {
+	n := f.doc
+	for _, part := range strings.Split(key, ".") {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		if n = mappingValue(n, part); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSetUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	input := "# My settings\nproduct:\n  edition: community # team license\n"
+	if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+
+	if err := f.Set("product.edition", "professional"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := f.Set("runtime.java.min_version", "21"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := f.Set("runtime.java.bundled.version", "21"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := f.Set("runtime.java.jvm_args", "-Xms1g, -XX:+UseG1GC"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := f.Set("runtime.java.min_version", "twenty"); err == nil {
+		t.Error("Set() with non-numeric int succeeded, want error")
+	}
+	if err := f.Set("product.colour", "blue"); err == nil {
+		t.Error("Set() with unknown key succeeded, want error")
+	}
+
+	if err := f.Write(); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# My settings", "edition: professional # team license", "min_version: 21", `version: "21"`, "- -XX:+UseG1GC"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("written file missing %q:\n%s", want, got)
+		}
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	if cfg.Runtime.Java.MinVersion != 21 || cfg.Runtime.Java.Bundled.Version != "21" {
+		t.Errorf("Config() = %+v", cfg.Runtime.Java)
+	}
+
+	removed, err := f.Unset("runtime.java.bundled.version")
+	if err != nil || !removed {
+		t.Fatalf("Unset() = %v, %v, want true", removed, err)
+	}
+	if _, ok := f.Get("runtime.java.bundled"); ok {
+		t.Error("Unset() left an empty bundled section")
+	}
+	if removed, _ := f.Unset("runtime.java.bundled.version"); removed {
+		t.Error("Unset() of missing key reported removed")
+	}
+	if v, ok := f.Get("runtime.java.min_version"); !ok || v != "21" {
+		t.Errorf("Get() = %q, %v, want 21", v, ok)
+	}
+}
+
+func TestFileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			input: "product:\n  edition: community\n",
+		},
+		{
+			name:    "invalid value points at its line and column",
+			input:   "product:\n  edition: community\nlayout:\n  mode: everywhere\n",
+			wantErr: "config.yaml:4:9: invalid layout.mode: everywhere",
+		},
+		{
+			name:    "all errors are reported",
+			input:   "layout:\n  mode: everywhere\nlogging:\n  level: loud\n",
+			wantErr: "2 errors:\n  config.yaml:2:9: invalid layout.mode: everywhere\n  config.yaml:4:10: invalid logging.level: loud",
+		},
+		{
+			name:    "unknown keys suggest a known key",
+			input:   "runtime:\n  java:\n    jvmargs: [-Xms1g]\n  heap: 50%\n",
+			wantErr: "config.yaml:3:5: unknown key runtime.java.jvmargs (did you mean runtime.java.jvm_args?)\n  config.yaml:4:3: unknown key runtime.heap (did you mean runtime.java.heap?)",
+		},
+		{
+			name:    "unknown key without a close match",
+			input:   "colour: blue\n",
+			wantErr: "config.yaml:1:1: unknown key colour",
+		},
+		{
+			name:    "type mismatch reports line",
+			input:   "network:\n  retries: many\n",
+			wantErr: "config.yaml:2: cannot unmarshal",
+		},
+		{
+			name:    "syntax error reports line",
+			input:   "product:\n  edition: [\n",
+			wantErr: "config.yaml:2: did not find expected node content",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFile("config.yaml", []byte(tt.input))
+			if err == nil {
+				err = f.Validate()
+			}
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+			var pe *PositionError
+			if !errors.As(err, &pe) {
+				t.Errorf("Validate() error = %T, want a *PositionError", err)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	data, err := Template()
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+
+	f, err := ParseFile("config.yaml", data)
+	if err != nil {
+		t.Fatalf("ParseFile(Template()) error = %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Template() is not valid: %v", err)
+	}
+	if !strings.Contains(string(data), "# Minimum required Java version") {
+		t.Errorf("Template() missing setting comments:\n%s", data)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// installsKey is the list of named installs in config files.
+const installsKey = "installs"
+
+// installNamePattern matches install names, which also name directories.
+var installNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// InstallNames returns the names of the declared installs in order.
+func (c Config) InstallNames() []string {
+	names := make([]string, len(c.Installs))
+	for i, in := range c.Installs {
+		names[i] = in.Name
+	}
+	return names
+}
+
+// Select returns the settings of the named install: the top-level
+// settings overlaid with the ones the install sets.
+func (c Config) Select(name string) (Config, error) {
+	in, err := c.install(name)
+	if err != nil {
+		return Config{}, err
+	}
+	c.apply(in)
+	return c, nil
+}
+
+// install finds the named install.
+func (c Config) install(name string) (InstallConfig, error) {
+	if len(c.Installs) == 0 {
+		return InstallConfig{}, fmt.Errorf("unknown install %q: no installs are declared", name)
+	}
+	for _, in := range c.Installs {
+		if in.Name == name {
+			return in, nil
+		}
+	}
+	return InstallConfig{}, fmt.Errorf("unknown install %q (declared: %s)", name, strings.Join(c.InstallNames(), ", "))
+}
+
+// apply overlays the settings the install sets onto c and returns their keys.
+func (c *Config) apply(in InstallConfig) []string {
+	var keys []string
+	overlay(reflect.ValueOf(c).Elem(), reflect.ValueOf(in), "", &keys)
+	c.InstallName = in.Name
+	return keys
+}
+
+// overlay copies the non-zero settings of src onto the fields of dst with
+// the same YAML names, recording each key it sets.
+func overlay(dst, src reflect.Value, prefix string, keys *[]string) {
+	for i := 0; i < src.NumField(); i++ {
+		name := yamlName(src.Type().Field(i))
+		d := dst.FieldByNameFunc(func(f string) bool {
+			sf, _ := dst.Type().FieldByName(f)
+			return yamlName(sf) == name
+		})
+		if name == "" || !d.IsValid() {
+			continue // Not a setting, such as the install name
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		s := src.Field(i)
+		if s.Kind() == reflect.Struct {
+			overlay(d, s, name, keys)
+			continue
+		}
+		if !s.IsZero() {
+			d.Set(s)
+			*keys = append(*keys, name)
+		}
+	}
+}
+
+// validateInstalls checks install names and that each install is valid
+// once its settings apply on top of c. The install named skip is left to
+// the caller, which validates it once selected.
+func (c Config) validateInstalls(skip string) []error {
+	var errs []error
+	seen := map[string]bool{}
+	for i, in := range c.Installs {
+		switch {
+		case in.Name == "":
+			errs = append(errs, fieldErrorf(installsKey, "installs entry %d needs a name", i+1))
+			continue
+		case !installNamePattern.MatchString(in.Name):
+			errs = append(errs, fieldErrorf(installsKey, "invalid install name %q (want lowercase letters, digits, '.', '_' or '-')", in.Name))
+			continue
+		case seen[in.Name]:
+			errs = append(errs, fieldErrorf(installsKey, "install %q is declared twice", in.Name))
+			continue
+		}
+		seen[in.Name] = true
+
+		if in.Name == skip {
+			continue
+		}
+		sel := c
+		sel.Installs = nil
+		sel.apply(in)
+		for _, e := range sel.validate() {
+			errs = append(errs, fieldErrorf(installsKey, "install %s: %v", in.Name, e))
+		}
+	}
+	return errs
+}
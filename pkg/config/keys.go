@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Keys returns the dotted name of every setting, e.g. "runtime.java.heap".
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := yamlName(f)
+		if name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			collectKeys(f.Type, name, keys)
+			continue
+		}
+		if isList(f.Type) {
+			continue // Lists of sections are edited in files, not by key
+		}
+		*keys = append(*keys, name)
+	}
+}
+
+// isList reports whether t is a list of sections, such as installs.
+func isList(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct
+}
+
+// listKeys returns the settings of each list of sections, prefixed with
+// the list's name, e.g. "installs.product.name".
+func listKeys() map[string][]string {
+	lists := map[string][]string{}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := yamlName(f); name != "" && isList(f.Type) {
+			var keys []string
+			collectKeys(f.Type.Elem(), name, &keys)
+			lists[name] = keys
+		}
+	}
+	return lists
+}
+
+// IsKey reports whether key names a setting.
+func IsKey(key string) bool {
+	_, err := field(reflect.ValueOf(&Config{}).Elem(), key)
+	return err == nil
+}
+
+// EnvVar returns the environment variable that overrides key,
+// e.g. RELAY_PRODUCT_EDITION for "product.edition".
+func EnvVar(key string) string {
+	return "RELAY_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Get returns the value of key formatted as it would be written on the
+// command line. Lists are comma-separated.
+func (c Config) Get(key string) (string, error) {
+	v, err := field(reflect.ValueOf(&c).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+	return formatValue(v), nil
+}
+
+// Set parses value into key. Lists are comma-separated.
+func (c *Config) Set(key, value string) error {
+	v, err := field(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return err
+	}
+	if err := parseValue(v, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// field walks the struct fields named by the dotted key.
+func field(v reflect.Value, key string) (reflect.Value, error) {
+	for _, part := range strings.Split(key, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown config key: %s", key)
+		}
+
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			if yamlName(v.Type().Field(i)) == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown config key: %s", key)
+		}
+	}
+
+	if v.Kind() == reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%s is a section, not a setting", key)
+	}
+	if isList(v.Type()) {
+		return reflect.Value{}, fmt.Errorf("%s is a list of sections; edit it in a config file", key)
+	}
+	return v, nil
+}
+
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func parseValue(v reflect.Value, value string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration: %s", value)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid number: %s", value)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean: %s", value)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items).Convert(v.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source identifies the layer a setting came from.
+type Source string
+
+// Layers in increasing precedence.
+const (
+	SourceDefault Source = "default"
+	SourceSystem  Source = "system"
+	SourceUser    Source = "user"
+	SourceProject Source = "project"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Origin records where the effective value of a setting came from.
+type Origin struct {
+	Source Source
+	Detail string // File path, environment variable or flag; empty for defaults
+	Line   int    // Position of the value in a file layer
+	Column int
+}
+
+func (o Origin) String() string {
+	switch {
+	case o.Detail == "":
+		return string(o.Source)
+	case o.Line > 0:
+		return fmt.Sprintf("%s (%s:%d)", o.Source, o.Detail, o.Line)
+	default:
+		return fmt.Sprintf("%s (%s)", o.Source, o.Detail)
+	}
+}
+
+// Origins maps each setting key to the layer that set it.
+type Origins map[string]Origin
+
+// LoadOptions lists the layers LoadLayered merges. Missing files are skipped.
+type LoadOptions struct {
+	SystemFile  string                      // Machine-wide config (e.g., /etc/relay/config.yaml)
+	UserFile    string                      // Per-user config in the config directory
+	ProjectFile string                      // Project-local config (e.g., ./config.yaml)
+	LookupEnv   func(string) (string, bool) // Environment lookup (default: os.LookupEnv)
+	Flags       map[string]string           // Command-line overrides by key
+	FlagNames   map[string]string           // Flag that set each override, for origins (optional)
+	Install     string                      // Named install to select after the files; see Config.Select
+}
+
+// LoadLayered builds the effective configuration from, in increasing
+// precedence: Default(), the system, user and project files, RELAY_*
+// environment variables and command-line flags. With opts.Install, the
+// install's settings override the files, and the environment and flags
+// still override those.
+func LoadLayered(opts LoadOptions) (Config, Origins, error) {
+	cfg := Default()
+	origins := Origins{}
+	for _, key := range Keys() {
+		origins[key] = Origin{Source: SourceDefault}
+	}
+	origins[installsKey] = Origin{Source: SourceDefault}
+
+	files := []struct {
+		source Source
+		path   string
+	}{
+		{SourceSystem, opts.SystemFile},
+		{SourceUser, opts.UserFile},
+		{SourceProject, opts.ProjectFile},
+	}
+	var errs []error
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if err := mergeFile(&cfg, origins, f.source, f.path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Installs are checked as declared, before selection and overrides;
+	// the selected one is checked with the final result
+	var installErrs []error
+	if len(errs) == 0 {
+		installErrs = cfg.validateInstalls(opts.Install)
+	}
+
+	if opts.Install != "" && len(errs) == 0 {
+		in, err := cfg.install(opts.Install)
+		if err != nil {
+			return Config{}, nil, err
+		}
+		for _, key := range cfg.apply(in) {
+			origins[key] = origins[installsKey]
+		}
+	}
+
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	for _, key := range Keys() {
+		name := EnvVar(key)
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := cfg.Set(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		origins[key] = Origin{Source: SourceEnv, Detail: name}
+	}
+
+	flagKeys := make([]string, 0, len(opts.Flags))
+	for key := range opts.Flags {
+		flagKeys = append(flagKeys, key)
+	}
+	sort.Strings(flagKeys)
+	for _, key := range flagKeys {
+		name := opts.FlagNames[key]
+		if name == "" {
+			name = "--set " + key
+		}
+		if err := cfg.Set(key, opts.Flags[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		origins[key] = Origin{Source: SourceFlag, Detail: name}
+	}
+
+	if origins[heapKey].Source == SourceDefault {
+		cfg.dropDefaultHeap()
+	}
+
+	// Report malformed layers before validating the partial result
+	if len(errs) > 0 {
+		return Config{}, nil, errorList(flatten(errs))
+	}
+
+	if verrs := append(cfg.validate(), installErrs...); len(verrs) > 0 {
+		return Config{}, nil, errorList(locate(verrs, origins))
+	}
+
+	return cfg, origins, nil
+}
+
+// locate points each validation error at the layer that set the value:
+// a file position, or the environment variable or flag.
+func locate(errs []error, origins Origins) []error {
+	out := make([]error, len(errs))
+	for i, err := range errs {
+		out[i] = err
+
+		fe, ok := err.(*FieldError)
+		if !ok {
+			continue
+		}
+		switch o := origins[fe.Key]; {
+		case o.Line > 0:
+			out[i] = &PositionError{Path: o.Detail, Line: o.Line, Column: o.Column, Err: fe}
+		case o.Source != SourceDefault:
+			out[i] = fmt.Errorf("%w (from %s)", fe, o)
+		}
+	}
+	return out
+}
+
+// flatten expands nested Errors so each problem is listed once.
+func flatten(errs []error) []error {
+	var out []error
+	for _, err := range errs {
+		if list, ok := err.(Errors); ok {
+			out = append(out, flatten(list)...)
+			continue
+		}
+		out = append(out, err)
+	}
+	return out
+}
+
+// mergeFile overlays the settings present in path onto cfg.
+func mergeFile(cfg *Config, origins Origins, source Source, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read %s config: %w", source, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return yamlError(path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil // Empty file
+	}
+
+	// Upgrade older files in memory; 'relay config migrate' rewrites them
+	if _, err := Migrate(doc.Content[0]); err != nil {
+		return atPath(path, err)
+	}
+
+	if err := decodeStrict(path, doc.Content[0], cfg); err != nil {
+		return err
+	}
+
+	for key, n := range nodeKeys(doc.Content[0], "") {
+		if _, ok := origins[key]; ok {
+			origins[key] = Origin{Source: source, Detail: path, Line: n.Line, Column: n.Column}
+		}
+	}
+
+	return nil
+}
+
+// nodeKeys returns the value nodes of the leaf settings in a YAML mapping
+// by dotted key.
+func nodeKeys(n *yaml.Node, prefix string) map[string]*yaml.Node {
+	keys := map[string]*yaml.Node{}
+	if n.Kind != yaml.MappingNode {
+		keys[prefix] = n
+		return keys
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i].Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		for k, v := range nodeKeys(n.Content[i+1], key) {
+			keys[k] = v
+		}
+	}
+	return keys
+}
+
+// ParseSetFlag splits a --set argument of the form key=value.
+func ParseSetFlag(arg string) (key, value string, err error) {
+	key, value, ok := strings.Cut(arg, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid --set %q: want key=value", arg)
+	}
+	if !IsKey(key) {
+		return "", "", fmt.Errorf("invalid --set %q: unknown config key: %s", arg, key)
+	}
+	return key, value, nil
+}
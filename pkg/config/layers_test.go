@@ -0,0 +1,319 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadLayered(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	system := write("system.yaml", "product:\n  edition: community\nnetwork:\n  retries: 5\n")
+	user := write("user.yaml", "network:\n  retries: 7\n  timeout: 1m\n")
+	project := write("project.yaml", "runtime:\n  java:\n    heap: 50%\n")
+
+	env := map[string]string{
+		"RELAY_NETWORK_TIMEOUT":       "45s",
+		"RELAY_RUNTIME_JAVA_HEAP":     "4g",
+		"RELAY_RUNTIME_JAVA_JVM_ARGS": "-Xms1g, -XX:+UseG1GC",
+	}
+
+	cfg, origins, err := LoadLayered(LoadOptions{
+		SystemFile:  system,
+		UserFile:    user,
+		ProjectFile: project,
+		LookupEnv: func(name string) (string, bool) {
+			v, ok := env[name]
+			return v, ok
+		},
+		Flags:     map[string]string{"runtime.java.heap": "2g"},
+		FlagNames: map[string]string{"runtime.java.heap": "--heap"},
+	})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	tests := []struct {
+		key        string
+		wantValue  string
+		wantOrigin Origin
+	}{
+		{"product.name", "burpsuite", Origin{Source: SourceDefault}},
+		{"product.edition", "community", Origin{Source: SourceSystem, Detail: system, Line: 2, Column: 12}},
+		{"network.retries", "7", Origin{Source: SourceUser, Detail: user, Line: 2, Column: 12}},
+		{"network.timeout", "45s", Origin{Source: SourceEnv, Detail: "RELAY_NETWORK_TIMEOUT"}},
+		{"runtime.java.heap", "2g", Origin{Source: SourceFlag, Detail: "--heap"}},
+		{"runtime.java.jvm_args", "-Xms1g,-XX:+UseG1GC", Origin{Source: SourceEnv, Detail: "RELAY_RUNTIME_JAVA_JVM_ARGS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got != tt.wantValue {
+				t.Errorf("Get() = %q, want %q", got, tt.wantValue)
+			}
+			if origins[tt.key] != tt.wantOrigin {
+				t.Errorf("origin = %v, want %v", origins[tt.key], tt.wantOrigin)
+			}
+		})
+	}
+
+	if cfg.Network.Timeout != 45*time.Second {
+		t.Errorf("Network.Timeout = %v, want 45s", cfg.Network.Timeout)
+	}
+}
+
+func TestLoadLayeredInstall(t *testing.T) {
+	project := filepath.Join(t.TempDir(), "project.yaml")
+	content := `product:
+  edition: professional
+installs:
+  - name: pro
+  - name: community
+    product:
+      edition: community
+      version: 2024.1.1
+    paths:
+      install: /opt/burp-community
+`
+	if err := os.WriteFile(project, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, origins, err := LoadLayered(LoadOptions{
+		ProjectFile: project,
+		LookupEnv:   func(string) (string, bool) { return "", false },
+		Flags:       map[string]string{"product.version": "2024.2.1"},
+		Install:     "community",
+	})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	if cfg.InstallName != "community" {
+		t.Errorf("InstallName = %q, want community", cfg.InstallName)
+	}
+	if cfg.Product.Edition != "community" || cfg.Paths.Install != "/opt/burp-community" {
+		t.Errorf("install settings not applied: %+v %+v", cfg.Product, cfg.Paths)
+	}
+	if cfg.Product.Version != "2024.2.1" {
+		t.Errorf("Product.Version = %q, want the flag to win", cfg.Product.Version)
+	}
+	if want := (Origin{Source: SourceProject, Detail: project, Line: 4, Column: 3}); origins["product.edition"] != want {
+		t.Errorf("origin = %v, want %v", origins["product.edition"], want)
+	}
+
+	if _, _, err := LoadLayered(LoadOptions{ProjectFile: project, Install: "beta"}); err == nil || !strings.Contains(err.Error(), "declared: pro, community") {
+		t.Errorf("unknown install error = %v", err)
+	}
+}
+
+func TestLoadLayeredInstallsValidatedAsDeclared(t *testing.T) {
+	project := filepath.Join(t.TempDir(), "project.yaml")
+	content := `installs:
+  - name: a
+    layout:
+      mode: shared
+      group: burp
+  - name: b
+    layout:
+      mode: portable
+`
+	if err := os.WriteFile(project, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	noEnv := func(string) (string, bool) { return "", false }
+
+	// a's group must not leak into b when a is selected, or the reverse
+	for _, name := range []string{"", "a", "b"} {
+		if _, _, err := LoadLayered(LoadOptions{ProjectFile: project, LookupEnv: noEnv, Install: name}); err != nil {
+			t.Errorf("LoadLayered(Install: %q) error = %v", name, err)
+		}
+	}
+
+	// A flag for the selected install doesn't apply to the others
+	_, _, err := LoadLayered(LoadOptions{
+		ProjectFile: project,
+		LookupEnv:   noEnv,
+		Flags:       map[string]string{"layout.group": "burp"},
+		Install:     "a",
+	})
+	if err != nil {
+		t.Errorf("LoadLayered() with flag error = %v", err)
+	}
+
+	// An install that is invalid as declared is reported whichever is selected
+	broken := filepath.Join(t.TempDir(), "broken.yaml")
+	if err := os.WriteFile(broken, []byte(content+"  - name: c\n    layout:\n      group: burp\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = LoadLayered(LoadOptions{ProjectFile: broken, LookupEnv: noEnv, Install: "a"})
+	if err == nil || !strings.Contains(err.Error(), "install c:") {
+		t.Errorf("LoadLayered() error = %v, want install c reported", err)
+	}
+}
+
+func TestLoadLayeredExplicitXmx(t *testing.T) {
+	dir := t.TempDir()
+	xmx := filepath.Join(dir, "xmx.yaml")
+	if err := os.WriteFile(xmx, []byte("config_version: 2\nruntime:\n  java:\n    jvm_args: [-Xms1g, -Xmx6g]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	both := filepath.Join(dir, "both.yaml")
+	if err := os.WriteFile(both, []byte("config_version: 2\nruntime:\n  java:\n    heap: 50%\n    jvm_args: [-Xmx6g]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	noEnv := func(string) (string, bool) { return "", false }
+
+	tests := []struct {
+		name     string
+		opts     LoadOptions
+		wantHeap string
+	}{
+		{
+			name:     "default heap",
+			opts:     LoadOptions{LookupEnv: noEnv},
+			wantHeap: "auto",
+		},
+		{
+			name:     "file -Xmx wins over default heap",
+			opts:     LoadOptions{ProjectFile: xmx, LookupEnv: noEnv},
+			wantHeap: "",
+		},
+		{
+			name: "env -Xmx wins over default heap",
+			opts: LoadOptions{LookupEnv: func(name string) (string, bool) {
+				if name == "RELAY_RUNTIME_JAVA_JVM_ARGS" {
+					return "-Xmx6g", true
+				}
+				return "", false
+			}},
+			wantHeap: "",
+		},
+		{
+			name:     "flag -Xmx wins over default heap",
+			opts:     LoadOptions{LookupEnv: noEnv, Flags: map[string]string{"runtime.java.jvm_args": "-Xmx6g"}},
+			wantHeap: "",
+		},
+		{
+			name:     "explicit heap wins over -Xmx",
+			opts:     LoadOptions{ProjectFile: both, LookupEnv: noEnv},
+			wantHeap: "50%",
+		},
+		{
+			name:     "flag heap wins over file -Xmx",
+			opts:     LoadOptions{ProjectFile: xmx, LookupEnv: noEnv, Flags: map[string]string{"runtime.java.heap": "4g"}},
+			wantHeap: "4g",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, _, err := LoadLayered(tt.opts)
+			if err != nil {
+				t.Fatalf("LoadLayered() error = %v", err)
+			}
+			if cfg.Runtime.Java.Heap != tt.wantHeap {
+				t.Errorf("Heap = %q, want %q", cfg.Runtime.Java.Heap, tt.wantHeap)
+			}
+		})
+	}
+}
+
+func TestLoadLayeredErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("product: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	typo := filepath.Join(dir, "typo.yaml")
+	if err := os.WriteFile(typo, []byte("layout:\n  mod: portable\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("logging:\n  level: loud\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	noEnv := func(string) (string, bool) { return "", false }
+
+	tests := []struct {
+		name    string
+		opts    LoadOptions
+		wantErr string
+	}{
+		{
+			name: "missing files are skipped",
+			opts: LoadOptions{SystemFile: filepath.Join(dir, "missing.yaml"), LookupEnv: noEnv},
+		},
+		{
+			name:    "malformed file",
+			opts:    LoadOptions{UserFile: bad, LookupEnv: noEnv},
+			wantErr: bad + ":1:",
+		},
+		{
+			name: "invalid environment value",
+			opts: LoadOptions{LookupEnv: func(name string) (string, bool) {
+				if name == "RELAY_NETWORK_RETRIES" {
+					return "many", true
+				}
+				return "", false
+			}},
+			wantErr: "RELAY_NETWORK_RETRIES",
+		},
+		{
+			name:    "unknown key in file",
+			opts:    LoadOptions{UserFile: typo, LookupEnv: noEnv},
+			wantErr: typo + ":2:3: unknown key layout.mod (did you mean layout.mode?)",
+		},
+		{
+			name:    "invalid value points at the file that set it",
+			opts:    LoadOptions{ProjectFile: invalid, LookupEnv: noEnv},
+			wantErr: invalid + ":2:10: invalid logging.level: loud",
+		},
+		{
+			name:    "override fails validation",
+			opts:    LoadOptions{LookupEnv: noEnv, Flags: map[string]string{"layout.mode": "everywhere"}},
+			wantErr: "invalid layout.mode: everywhere (from flag (--set layout.mode))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := LoadLayered(tt.opts)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("LoadLayered() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadLayered() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnvVar(t *testing.T) {
+	if got := EnvVar("product.edition"); got != "RELAY_PRODUCT_EDITION" {
+		t.Errorf("EnvVar() = %v", got)
+	}
+	if got := EnvVar("runtime.java.min_version"); got != "RELAY_RUNTIME_JAVA_MIN_VERSION" {
+		t.Errorf("EnvVar() = %v", got)
+	}
+}
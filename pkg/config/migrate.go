@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// migration upgrades a config document from version From to From+1.
+type migration struct {
+	From        int
+	Description string
+	Apply       func(doc *yaml.Node) error
+}
+
+// migrations is the upgrade chain, one entry per version bump.
+var migrations = []migration{
+	{
+		From:        1,
+		Description: "move -Xmx from runtime.java.jvm_args to runtime.java.heap",
+		Apply:       migrateHeapArg,
+	},
+}
+
+// Migration describes an upgrade applied to a config document.
+type Migration struct {
+	From        int
+	To          int
+	Description string
+}
+
+// DocumentVersion returns the config_version of a config mapping.
+// Documents written before versioning are version 1.
+func DocumentVersion(doc *yaml.Node) (int, error) {
+	n := mappingValue(doc, "config_version")
+	if n == nil {
+		return 1, nil
+	}
+
+	v, err := strconv.Atoi(n.Value)
+	if err != nil || v < 1 {
+		return 0, &PositionError{Line: n.Line, Column: n.Column, Err: fmt.Errorf("invalid config_version: %s", n.Value)}
+	}
+	return v, nil
+}
+
+// Migrate upgrades a config mapping in place to CurrentVersion and returns
+// the migrations applied. Documents from a newer relay are rejected rather
+// than misread.
+func Migrate(doc *yaml.Node) ([]Migration, error) {
+	version, err := DocumentVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config_version %d is newer than this relay supports (%d); upgrade relay", version, CurrentVersion)
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.From < version {
+			continue
+		}
+		if err := m.Apply(doc); err != nil {
+			return nil, fmt.Errorf("migrate config from version %d: %w", m.From, err)
+		}
+		applied = append(applied, Migration{From: m.From, To: m.From + 1, Description: m.Description})
+	}
+
+	if len(applied) > 0 {
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentVersion)}
+		if n := mappingValue(doc, "config_version"); n != nil {
+			*n = *value
+		} else {
+			// Keep the version first, where readers look for it
+			key := &yaml.Node{Kind: yaml.ScalarNode, Value: "config_version"}
+			doc.Content = append([]*yaml.Node{key, value}, doc.Content...)
+		}
+	}
+	return applied, nil
+}
+
+// migrateHeapArg moves a -Xmx entry into runtime.java.heap, which from
+// version 2 replaces any -Xmx in jvm_args. Files that already set heap
+// are left as they are.
+func migrateHeapArg(doc *yaml.Node) error {
+	java := mappingPath(doc, "runtime", "java")
+	if java == nil || mappingValue(java, "heap") != nil {
+		return nil
+	}
+
+	args := mappingValue(java, "jvm_args")
+	if args == nil || args.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for i, arg := range args.Content {
+		size, ok := strings.CutPrefix(arg.Value, "-Xmx")
+		if !ok {
+			continue
+		}
+		args.Content = append(args.Content[:i], args.Content[i+1:]...)
+		setMappingValue(java, "heap", &yaml.Node{Kind: yaml.ScalarNode, Value: size})
+		return nil
+	}
+	return nil
+}
+
+// mappingPath follows nested mapping keys, returning nil if any is missing.
+func mappingPath(m *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if m == nil || m.Kind != yaml.MappingNode {
+			return nil
+		}
+		m = mappingValue(m, key)
+	}
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	return m
+}
+
+// cloneNode deep-copies a YAML node so a migration can run without
+// changing the document being edited.
+func cloneNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = cloneNode(child)
+	}
+	return &c
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantApplied int
+		wantHeap    string
+		wantArgs    []string
+		wantErr     string
+	}{
+		{
+			name:        "v1 moves -Xmx to heap",
+			input:       "runtime:\n  java:\n    jvm_args: [-Xmx4g, -Xms1g]\n",
+			wantApplied: 1,
+			wantHeap:    "4g",
+			wantArgs:    []string{"-Xms1g"},
+		},
+		{
+			name:        "v1 keeps explicit heap",
+			input:       "runtime:\n  java:\n    heap: 50%\n    jvm_args: [-Xmx4g]\n",
+			wantApplied: 1,
+			wantHeap:    "50%",
+			wantArgs:    []string{"-Xmx4g"},
+		},
+		{
+			name:        "v1 without java settings",
+			input:       "product:\n  edition: community\n",
+			wantApplied: 1,
+			wantHeap:    "auto",
+		},
+		{
+			name:     "current version untouched, -Xmx wins over default heap",
+			input:    "config_version: 2\nruntime:\n  java:\n    jvm_args: [-Xmx4g]\n",
+			wantHeap: "",
+			wantArgs: []string{"-Xmx4g"},
+		},
+		{
+			name:    "newer version rejected",
+			input:   "config_version: 99\n",
+			wantErr: "config_version 99 is newer",
+		},
+		{
+			name:    "invalid version",
+			input:   "config_version: two\n",
+			wantErr: "config.yaml:1:17: invalid config_version: two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFile("config.yaml", []byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParseFile() error = %v", err)
+			}
+
+			applied, err := f.Migrate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Migrate() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			if len(applied) != tt.wantApplied {
+				t.Errorf("Migrate() applied %d migrations, want %d", len(applied), tt.wantApplied)
+			}
+
+			if v, _ := f.Version(); v != CurrentVersion {
+				t.Errorf("Version() after Migrate() = %d, want %d", v, CurrentVersion)
+			}
+
+			cfg, err := f.Config()
+			if err != nil {
+				t.Fatalf("Config() error = %v", err)
+			}
+			if cfg.Runtime.Java.Heap != tt.wantHeap {
+				t.Errorf("Heap = %q, want %q", cfg.Runtime.Java.Heap, tt.wantHeap)
+			}
+			if tt.wantArgs != nil && strings.Join(cfg.Runtime.Java.JVMArgs, " ") != strings.Join(tt.wantArgs, " ") {
+				t.Errorf("JVMArgs = %v, want %v", cfg.Runtime.Java.JVMArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFileConfigMigratesInMemory(t *testing.T) {
+	input := "runtime:\n  java:\n    jvm_args: [-Xmx2g]\n"
+	f, err := ParseFile("config.yaml", []byte(input))
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	if cfg.Runtime.Java.Heap != "2g" {
+		t.Errorf("Heap = %q, want 2g", cfg.Runtime.Java.Heap)
+	}
+
+	// The document itself stays at version 1 until migrated explicitly
+	if v, _ := f.Version(); v != 1 {
+		t.Errorf("Version() = %d, want 1", v)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	data, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema() is not valid JSON: %v", err)
+	}
+
+	// Every setting must be described
+	for _, key := range Keys() {
+		node := schema
+		for _, part := range strings.Split(key, ".") {
+			props, ok := node["properties"].(map[string]any)
+			if !ok {
+				t.Fatalf("schema has no properties above %s", key)
+			}
+			if node, ok = props[part].(map[string]any); !ok {
+				t.Fatalf("schema missing %s", key)
+			}
+		}
+		if _, ok := node["type"]; !ok {
+			t.Errorf("schema for %s has no type", key)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// schemaHints adds constraints reflection can't see: enumerations and
+// the patterns Validate enforces.
+var schemaHints = map[string]map[string]any{
+	"config_version":                {"minimum": 1, "maximum": CurrentVersion},
+	"product.name":                  {"enum": []string{"burpsuite", "zap"}},
+	"product.edition":               {"enum": []string{"professional", "community"}},
+	"layout.mode":                   {"enum": []LayoutMode{SystemLayout, PortableLayout, SharedLayout}},
+	"runtime.java.strategy":         {"enum": []JavaStrategy{JavaStrategyAuto, JavaStrategySystem, JavaStrategyBundled, JavaStrategyPath, JavaStrategyManaged}},
+	"runtime.java.min_version":      {"minimum": 8},
+	"runtime.java.heap":             {"pattern": heapPattern.String()},
+	"runtime.java.bundled.version":  {"pattern": `^(latest|(jdk-)?([89]|[1-9]\d+|\d+(\.\d+)*\+\d+))$`},
+	"runtime.java.bundled.metadata": {"format": "uri", "pattern": "^https?://"},
+	"network.timeout":               {"pattern": `^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`},
+	"network.retries":               {"minimum": 0},
+	"logging.level":                 {"enum": []LogLevel{LogLevelInfo, LogLevelDebug, LogLevelTrace}},
+}
+
+// Schema returns a JSON Schema (draft 2020-12) describing config files,
+// generated from the Config types.
+func Schema() ([]byte, error) {
+	root := typeSchema(reflect.TypeOf(Config{}), "")
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = fmt.Sprintf("relay configuration (config_version %d)", CurrentVersion)
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func typeSchema(t reflect.Type, key string) map[string]any {
+	var s map[string]any
+
+	switch {
+	case t == durationType:
+		s = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Struct:
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := yamlName(f)
+			if name == "" {
+				continue
+			}
+			child := name
+			if key != "" {
+				child = key + "." + name
+			}
+			props[name] = typeSchema(f.Type, child)
+		}
+		s = map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	case t.Kind() == reflect.Slice:
+		s = map[string]any{"type": "array", "items": typeSchema(t.Elem(), "")}
+	case t.Kind() == reflect.String:
+		s = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Int:
+		s = map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Bool:
+		s = map[string]any{"type": "boolean"}
+	default:
+		s = map[string]any{}
+	}
+
+	if doc := keyDocs[key]; doc != "" {
+		s["description"] = doc
+	}
+	for k, v := range schemaHints[key] {
+		s[k] = v
+	}
+	return s
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeStrict decodes a config mapping onto cfg. Unlike yaml.Unmarshal it
+// rejects keys that are not settings, suggesting the closest known key, and
+// reports every unknown key and type mismatch with its position in path.
+func decodeStrict(path string, m *yaml.Node, cfg *Config) error {
+	errs := unknownKeys(path, m, "")
+	if len(errs) > 0 {
+		return errorList(errs)
+	}
+
+	if err := m.Decode(cfg); err != nil {
+		return errorList(yamlErrors(path, err))
+	}
+	return nil
+}
+
+// unknownKeys returns a PositionError for every key in m that names
+// neither a setting nor a section.
+func unknownKeys(path string, m *yaml.Node, prefix string) []error {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	known := knownKeys()
+	var errs []error
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k := m.Content[i]
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch known[key] {
+		case keySection:
+			errs = append(errs, unknownKeys(path, m.Content[i+1], key)...)
+		case keyList:
+			if v := m.Content[i+1]; v.Kind == yaml.SequenceNode {
+				for _, item := range v.Content {
+					errs = append(errs, unknownKeys(path, item, key)...)
+				}
+			}
+		case keySetting:
+		default:
+			msg := fmt.Sprintf("unknown key %s", key)
+			if s := suggestKey(key); s != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", s)
+			}
+			errs = append(errs, &PositionError{Path: path, Line: k.Line, Column: k.Column, Err: errors.New(msg)})
+		}
+	}
+	return errs
+}
+
+type keyKind int
+
+const (
+	keyUnknown keyKind = iota
+	keySection
+	keySetting
+	keyList // List of sections; its items hold the list's settings
+)
+
+// knownKeys returns every setting and every section containing one.
+// Settings of list items are prefixed with the list's name.
+func knownKeys() map[string]keyKind {
+	known := map[string]keyKind{}
+	add := func(key string) {
+		known[key] = keySetting
+		for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+			known[key[:i]] = keySection
+		}
+	}
+
+	for _, key := range Keys() {
+		add(key)
+	}
+	for list, keys := range listKeys() {
+		for _, key := range keys {
+			add(key)
+		}
+		known[list] = keyList
+	}
+	return known
+}
+
+// suggestKey returns the known key closest to an unknown one: a sibling
+// with a similar name, or else a key of the same name in another section.
+func suggestKey(key string) string {
+	parent, name := splitKey(key)
+
+	// Suggest keys of list items only inside a list, and the reverse
+	inList := strings.HasPrefix(key, installsKey+".")
+
+	var sibling, moved string
+	bestDist := maxSuggestDistance(name) + 1
+	for known := range knownKeys() {
+		if strings.HasPrefix(known, installsKey+".") != inList {
+			continue
+		}
+		kParent, kName := splitKey(known)
+
+		if kParent != parent {
+			if kName == name && (moved == "" || known < moved) {
+				moved = known
+			}
+			continue
+		}
+
+		d := levenshtein(name, kName)
+		if d < bestDist || (d == bestDist && known < sibling) {
+			sibling, bestDist = known, d
+		}
+	}
+
+	if sibling != "" {
+		return sibling
+	}
+	return moved
+}
+
+func splitKey(key string) (parent, name string) {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", key
+}
+
+func maxSuggestDistance(name string) int {
+	if n := len(name) / 3; n > 1 {
+		return n
+	}
+	return 1
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
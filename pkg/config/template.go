@@ -0,0 +1,91 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keyDocs describes sections and settings in the file written by Template.
+var keyDocs = map[string]string{
+	"config_version":                "Schema version; 'relay config migrate' upgrades older files",
+	"product":                       "Product configuration",
+	"product.name":                  `Product to manage: "burpsuite" or "zap" (OWASP ZAP, needs an exact product.version)`,
+	"product.edition":               `"professional" or "community"`,
+	"product.version":               `Version string or "latest"`,
+	"layout":                        "Layout configuration",
+	"layout.mode":                   `"system", "portable" or "shared"`,
+	"layout.group":                  "Group that can read and update a shared install",
+	"paths":                         "Path hints (optional, usually auto-detected)",
+	"paths.install":                 "Installation directory",
+	"paths.data":                    "Data directory",
+	"paths.bin":                     "Binary/launcher directory",
+	"runtime":                       "Runtime configuration",
+	"runtime.java.strategy":         `"auto", "system", "bundled", "path" or "managed"`,
+	"runtime.java.java_home":        `Java home directory (required for "path")`,
+	"runtime.java.min_version":      "Minimum required Java version",
+	"runtime.java.heap":             `Max heap: "auto", a percentage ("50%") or a size ("4g")`,
+	"runtime.java.jvm_args":         "JVM arguments passed to Java",
+	"runtime.java.bundled":          "JRE downloaded when no suitable Java is found",
+	"runtime.java.bundled.version":  `Feature version, exact release ("21.0.5+11") or "latest"`,
+	"runtime.java.bundled.metadata": "Temurin release metadata endpoint",
+	"desktop":                       "Desktop integration",
+	"desktop.shortcut":              "Create an application menu shortcut for the launcher",
+	"burp":                          "Burp Suite settings",
+	"burp.proxy_listeners":          "Proxy listener addresses (host:port) to check; empty reads Burp's options",
+	"backup":                        "Backups of Burp and relay settings and the data directory",
+	"backup.auto":                   "Back up automatically before update and remove",
+	"backup.dir":                    `Archive directory ("auto": backups/ in the config directory)`,
+	"network":                       "Network configuration",
+	"network.timeout":               "Download timeout",
+	"network.retries":               "Number of retry attempts",
+	"logging":                       "Logging configuration",
+	"logging.level":                 `"info", "debug", or "trace"`,
+	"installs":                      "Named installs; each overrides product, layout, paths, runtime and desktop settings",
+}
+
+// Template returns Default() as YAML with a comment on each setting.
+func Template() ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(Default()); err != nil {
+		return nil, fmt.Errorf("encode defaults: %w", err)
+	}
+	doc.HeadComment = "relay configuration. Run 'relay config show --origin' to see effective values."
+	annotate(&doc, "")
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode defaults: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode defaults: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// annotate attaches keyDocs to a mapping: sections get a comment above,
+// settings a comment at the end of the line.
+func annotate(m *yaml.Node, prefix string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k, v := m.Content[i], m.Content[i+1]
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		doc := keyDocs[key]
+		if v.Kind == yaml.MappingNode {
+			if doc != "" {
+				k.HeadComment = doc
+			}
+			annotate(v, key)
+			continue
+		}
+		if doc != "" {
+			k.LineComment = doc
+		}
+	}
+}
@@ -2,38 +2,187 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
+// bundledVersionPattern matches a Temurin feature version from 8 on ("21")
+// or an exact release, which needs its build ("21.0.5+11", "jdk-21.0.5+11").
+var bundledVersionPattern = regexp.MustCompile(`^(jdk-)?([89]|[1-9]\d+|\d+(\.\d+)*\+\d+)$`)
+
+// heapPattern matches runtime.java.heap and -Xmx values: "auto", a
+// percentage of host memory ("50%") or a JVM size ("4g", "4096m").
+var heapPattern = regexp.MustCompile(`^(auto|([1-9]\d?|100)%|\d+[kKmMgGtT]?)$`)
+
+// exactReleasePattern matches only exact releases, as required for pinning.
+var exactReleasePattern = regexp.MustCompile(`^(jdk-)?\d+(\.\d+)*\+\d+$`)
+
+// FieldError is a validation error for a single setting.
+type FieldError struct {
+	Key     string // Dotted key, e.g. "layout.mode"
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func fieldErrorf(key, format string, args ...any) error {
+	return &FieldError{Key: key, Message: fmt.Sprintf(format, args...)}
+}
+
+// Errors collects every problem found in a configuration.
+type Errors []error
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = "  " + err.Error()
+	}
+	return fmt.Sprintf("%d errors:\n%s", len(e), strings.Join(msgs, "\n"))
+}
+
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// errorList returns errs as an error, or nil when empty.
+func errorList(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return Errors(errs)
+}
+
+// Validate checks every setting and returns all problems as Errors
+// of *FieldError. Declared installs are checked on top of c, so c should
+// be the top-level config rather than a selected install.
 func (c Config) Validate() error {
+	return errorList(append(c.validate(), c.validateInstalls("")...))
+}
+
+// validate checks the settings of c itself, leaving out the installs list.
+func (c Config) validate() []error {
+	var errs []error
+
+	// Zero is a Config built in code rather than loaded from a file
+	if c.ConfigVersion != 0 && c.ConfigVersion != CurrentVersion {
+		errs = append(errs, fieldErrorf("config_version", "unsupported config_version: %d (want %d)", c.ConfigVersion, CurrentVersion))
+	}
+
 	if c.Product.Name == "" {
-		return fmt.Errorf("product.name is required")
+		errs = append(errs, fieldErrorf("product.name", "product.name is required"))
 	}
 
 	if c.Product.Version == "" {
-		return fmt.Errorf("product.version is required")
+		errs = append(errs, fieldErrorf("product.version", "product.version is required"))
 	}
 
 	switch c.Layout.Mode {
-	case SystemLayout, PortableLayout:
+	case SystemLayout, PortableLayout, SharedLayout:
 	default:
-		return fmt.Errorf("invalid layout.mode: %s", c.Layout.Mode)
+		errs = append(errs, fieldErrorf("layout.mode", "invalid layout.mode: %s", c.Layout.Mode))
+	}
+
+	if c.Layout.Group != "" && c.Layout.Mode != SharedLayout {
+		errs = append(errs, fieldErrorf("layout.group", "layout.group requires layout.mode shared"))
+	}
+
+	hints := []struct{ key, value string }{
+		{"paths.install", c.Paths.Install},
+		{"paths.data", c.Paths.Data},
+		{"paths.bin", c.Paths.Bin},
+		{"backup.dir", c.Backup.Dir},
+	}
+	for _, h := range hints {
+		if !validPathHint(h.value) {
+			errs = append(errs, fieldErrorf(h.key, "%s must be auto or an absolute path: %s", h.key, h.value))
+		}
 	}
 
 	switch c.Runtime.Java.Strategy {
-	case JavaStrategyAuto, JavaStrategySystem:
+	case JavaStrategyAuto, JavaStrategySystem, JavaStrategyBundled:
+	case JavaStrategyPath:
+		if c.Runtime.Java.JavaHome == "" {
+			errs = append(errs, fieldErrorf("runtime.java.java_home", "runtime.java.java_home is required for strategy path"))
+		} else if !filepath.IsAbs(c.Runtime.Java.JavaHome) {
+			errs = append(errs, fieldErrorf("runtime.java.java_home", "runtime.java.java_home must be absolute: %s", c.Runtime.Java.JavaHome))
+		}
+	case JavaStrategyManaged:
+		if !exactReleasePattern.MatchString(c.Runtime.Java.Bundled.Version) {
+			errs = append(errs, fieldErrorf("runtime.java.bundled.version", "strategy managed requires runtime.java.bundled.version to pin an exact release (e.g., 21.0.5+11)"))
+		}
 	default:
-		return fmt.Errorf("invalid runtime.java.strategy: %s", c.Runtime.Java.Strategy)
+		errs = append(errs, fieldErrorf("runtime.java.strategy", "invalid runtime.java.strategy: %s", c.Runtime.Java.Strategy))
 	}
 
 	if c.Runtime.Java.MinVersion < 8 {
-		return fmt.Errorf("runtime.java.min_version must be >= 8")
+		errs = append(errs, fieldErrorf("runtime.java.min_version", "runtime.java.min_version must be >= 8"))
+	}
+
+	if h := c.Runtime.Java.Heap; h != "" && !heapPattern.MatchString(h) {
+		errs = append(errs, fieldErrorf("runtime.java.heap", "invalid runtime.java.heap: %s (want auto, a percentage like 50%% or a size like 4g)", h))
+	}
+
+	for _, arg := range c.Runtime.Java.JVMArgs {
+		if v, ok := strings.CutPrefix(arg, "-Xmx"); ok && !heapPattern.MatchString(v) {
+			errs = append(errs, fieldErrorf("runtime.java.jvm_args", "invalid runtime.java.jvm_args entry: %s", arg))
+		}
+	}
+
+	if v := c.Runtime.Java.Bundled.Version; v != "" && v != "latest" && !bundledVersionPattern.MatchString(v) {
+		errs = append(errs, fieldErrorf("runtime.java.bundled.version", "invalid runtime.java.bundled.version: %s (want a feature version such as 21, an exact release such as 21.0.5+11, or latest)", v))
+	}
+
+	if m := c.Runtime.Java.Bundled.Metadata; m != "" {
+		u, err := url.Parse(m)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fieldErrorf("runtime.java.bundled.metadata", "invalid runtime.java.bundled.metadata: %s", m))
+		}
+	}
+
+	for _, addr := range c.Burp.ProxyListeners {
+		if !validListener(addr) {
+			errs = append(errs, fieldErrorf("burp.proxy_listeners", "invalid burp.proxy_listeners entry: %s (want host:port)", addr))
+		}
 	}
 
 	switch c.Logging.Level {
 	case LogLevelInfo, LogLevelDebug, LogLevelTrace:
 	default:
-		return fmt.Errorf("invalid logging.level: %s", c.Logging.Level)
+		errs = append(errs, fieldErrorf("logging.level", "invalid logging.level: %s", c.Logging.Level))
 	}
 
-	return nil
+	return errs
+}
+
+// validListener reports whether addr is a host:port a listener can bind.
+func validListener(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// validPathHint reports whether a paths.* value is "auto" or can expand to
+// an absolute path: absolute already, or starting with ~ or a variable.
+func validPathHint(hint string) bool {
+	switch {
+	case hint == "", hint == "auto", filepath.IsAbs(hint):
+		return true
+	case hint == "~", strings.HasPrefix(hint, "~/"), strings.HasPrefix(hint, `~\`):
+		return true
+	default:
+		return strings.HasPrefix(hint, "$") || strings.HasPrefix(hint, "%")
+	}
 }
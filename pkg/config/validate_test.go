@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -55,6 +56,157 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: "invalid runtime.java.strategy",
 		},
+		{
+			name: "bundled java strategy valid",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyBundled, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+		},
+		{
+			name: "path java strategy requires java_home",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyPath, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "java_home is required",
+		},
+		{
+			name: "path java strategy requires absolute java_home",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyPath, JavaHome: "jdk-21", MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "java_home must be absolute",
+		},
+		{
+			name: "managed java strategy requires exact release",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{
+					Strategy:   JavaStrategyManaged,
+					MinVersion: 17,
+					Bundled:    BundledJavaConfig{Version: "21"},
+				}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "pin an exact release",
+		},
+		{
+			name: "managed java strategy with pinned release",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{
+					Strategy:   JavaStrategyManaged,
+					MinVersion: 17,
+					Bundled:    BundledJavaConfig{Version: "21.0.5+11"},
+				}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+		},
+		{
+			name: "invalid bundled java version",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{
+					Strategy:   JavaStrategyAuto,
+					MinVersion: 17,
+					Bundled:    BundledJavaConfig{Version: "newest"},
+				}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "invalid runtime.java.bundled.version",
+		},
+		{
+			name: "bundled java release without build",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{
+					Strategy:   JavaStrategyAuto,
+					MinVersion: 17,
+					Bundled:    BundledJavaConfig{Version: "21.0.5"},
+				}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "invalid runtime.java.bundled.version",
+		},
+		{
+			name: "bundled java feature before 8",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{
+					Strategy:   JavaStrategyAuto,
+					MinVersion: 17,
+					Bundled:    BundledJavaConfig{Version: "7"},
+				}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "invalid runtime.java.bundled.version",
+		},
+		{
+			name: "relative path hint",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Paths:   PathsConfig{Data: "burp-data", Bin: "~/bin"},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "paths.data must be auto or an absolute path",
+		},
+		{
+			name: "relative backup dir",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Backup:  BackupConfig{Dir: "backups"},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "backup.dir must be auto or an absolute path",
+		},
+		{
+			name: "invalid proxy listener",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Burp:    BurpConfig{ProxyListeners: []string{"127.0.0.1:8080", "8081"}},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "invalid burp.proxy_listeners entry: 8081",
+		},
+		{
+			name: "invalid heap expression",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17, Heap: "half"}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "invalid runtime.java.heap",
+		},
+		{
+			name: "invalid heap expression in jvm_args",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17, JVMArgs: []string{"-Xmx200%"}}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "invalid runtime.java.jvm_args entry",
+		},
 		{
 			name: "java min version too low",
 			config: Config{
@@ -84,6 +236,49 @@ func TestValidate(t *testing.T) {
 				Logging: LoggingConfig{Level: LogLevelDebug},
 			},
 		},
+		{
+			name: "shared layout with group valid",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SharedLayout, Group: "burp"},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+		},
+		{
+			name: "group without shared layout",
+			config: Config{
+				Product: ProductConfig{Name: "burpsuite", Version: "latest"},
+				Layout:  LayoutConfig{Mode: SystemLayout, Group: "burp"},
+				Runtime: RuntimeConfig{Java: JavaConfig{Strategy: JavaStrategyAuto, MinVersion: 17}},
+				Logging: LoggingConfig{Level: LogLevelInfo},
+			},
+			wantErr: "layout.group requires layout.mode shared",
+		},
+		{
+			name:    "install without a name",
+			config:  withInstalls(InstallConfig{Product: ProductConfig{Edition: "community"}}),
+			wantErr: "installs entry 1 needs a name",
+		},
+		{
+			name:    "install name used twice",
+			config:  withInstalls(InstallConfig{Name: "pro"}, InstallConfig{Name: "pro"}),
+			wantErr: `install "pro" is declared twice`,
+		},
+		{
+			name:    "install name is not a directory name",
+			config:  withInstalls(InstallConfig{Name: "Burp Pro"}),
+			wantErr: `invalid install name "Burp Pro"`,
+		},
+		{
+			name:    "invalid install settings",
+			config:  withInstalls(InstallConfig{Name: "pro", Layout: LayoutConfig{Mode: "everywhere"}}),
+			wantErr: "install pro: invalid layout.mode: everywhere",
+		},
+		{
+			name:   "valid installs",
+			config: withInstalls(InstallConfig{Name: "pro"}, InstallConfig{Name: "community", Product: ProductConfig{Edition: "community"}}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -109,6 +304,29 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateCollectsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Layout.Mode = "everywhere"
+	cfg.Logging.Level = "loud"
+	cfg.Runtime.Java.MinVersion = 6
+
+	err := cfg.Validate()
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() error = %v, want Errors", err)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("Validate() returned %d errors, want 3: %v", len(errs), err)
+	}
+	for i, key := range []string{"layout.mode", "runtime.java.min_version", "logging.level"} {
+		var fe *FieldError
+		if !errors.As(errs[i], &fe) || fe.Key != key {
+			t.Errorf("error %d = %v, want FieldError for %s", i, errs[i], key)
+		}
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := Default()
 
@@ -167,3 +385,9 @@ func TestDefaultJVMArgs(t *testing.T) {
 		t.Error("Default JVM args should include lang module open")
 	}
 }
+
+func withInstalls(installs ...InstallConfig) Config {
+	cfg := Default()
+	cfg.Installs = installs
+	return cfg
+}